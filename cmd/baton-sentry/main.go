@@ -11,6 +11,7 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/connectorbuilder"
 	"github.com/conductorone/baton-sdk/pkg/field"
 	"github.com/conductorone/baton-sdk/pkg/types"
+	"github.com/conductorone/baton-sentry/pkg/client"
 	cfg "github.com/conductorone/baton-sentry/pkg/config"
 	"github.com/conductorone/baton-sentry/pkg/connector"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
@@ -49,7 +50,21 @@ func getConnector[T field.Configurable](ctx context.Context, config T) (types.Co
 		return nil, err
 	}
 
-	cb, err := connector.New(ctx, config.GetString(cfg.ApiToken.FieldName))
+	orgTokens, err := cfg.ParseOrgTokens(config.GetStringSlice(cfg.OrgTokens.FieldName))
+	if err != nil {
+		return nil, err
+	}
+
+	scimTokens, err := cfg.ParseOrgTokens(config.GetStringSlice(cfg.ScimTokens.FieldName))
+	if err != nil {
+		return nil, err
+	}
+
+	cb, err := connector.New(ctx, config.GetString(cfg.ApiToken.FieldName),
+		client.WithOrgTokens(orgTokens),
+		client.WithSCIMTokens(scimTokens),
+		client.WithDryRun(config.GetBool(cfg.DryRun.FieldName)),
+	)
 	if err != nil {
 		l.Error("error creating connector", zap.Error(err))
 		return nil, err
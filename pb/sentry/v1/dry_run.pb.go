@@ -0,0 +1,210 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.5
+// 	protoc        v5.29.3
+// source: pb/sentry/v1/dry_run.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DryRun marks a provisioning call as simulated, dry-run mode skipped the requests that would have changed data
+// in Sentry.
+type DryRun struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// requests lists the skipped requests, in the order they would have been sent.
+	Requests      []*DryRun_Request `protobuf:"bytes,1,rep,name=requests,proto3" json:"requests,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRun) Reset() {
+	*x = DryRun{}
+	mi := &file_pb_sentry_v1_dry_run_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRun) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRun) ProtoMessage() {}
+
+func (x *DryRun) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_sentry_v1_dry_run_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRun.ProtoReflect.Descriptor instead.
+func (*DryRun) Descriptor() ([]byte, []int) {
+	return file_pb_sentry_v1_dry_run_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DryRun) GetRequests() []*DryRun_Request {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// Request is a request dry-run mode skipped.
+type DryRun_Request struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"`
+	Method        string                 `protobuf:"bytes,2,opt,name=method,proto3" json:"method,omitempty"`
+	Url           string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	Body          string                 `protobuf:"bytes,4,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRun_Request) Reset() {
+	*x = DryRun_Request{}
+	mi := &file_pb_sentry_v1_dry_run_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRun_Request) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRun_Request) ProtoMessage() {}
+
+func (x *DryRun_Request) ProtoReflect() protoreflect.Message {
+	mi := &file_pb_sentry_v1_dry_run_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRun_Request.ProtoReflect.Descriptor instead.
+func (*DryRun_Request) Descriptor() ([]byte, []int) {
+	return file_pb_sentry_v1_dry_run_proto_rawDescGZIP(), []int{0, 0}
+}
+
+func (x *DryRun_Request) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *DryRun_Request) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *DryRun_Request) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *DryRun_Request) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+var File_pb_sentry_v1_dry_run_proto protoreflect.FileDescriptor
+
+var file_pb_sentry_v1_dry_run_proto_rawDesc = string([]byte{
+	0x0a, 0x1a, 0x70, 0x62, 0x2f, 0x73, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x2f, 0x76, 0x31, 0x2f, 0x64,
+	0x72, 0x79, 0x5f, 0x72, 0x75, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x73, 0x65,
+	0x6e, 0x74, 0x72, 0x79, 0x2e, 0x76, 0x31, 0x22, 0xa0, 0x01, 0x0a, 0x06, 0x44, 0x72, 0x79, 0x52,
+	0x75, 0x6e, 0x12, 0x35, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x18, 0x01,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x73, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x72, 0x79, 0x52, 0x75, 0x6e, 0x2e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52,
+	0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x1a, 0x5f, 0x0a, 0x07, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x16, 0x0a, 0x06,
+	0x6d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x65,
+	0x74, 0x68, 0x6f, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x75, 0x72, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x03, 0x75, 0x72, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x62, 0x6f, 0x64, 0x79, 0x42, 0x33, 0x5a, 0x31, 0x67, 0x69,
+	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x63, 0x6f, 0x6e, 0x64, 0x75, 0x63, 0x74,
+	0x6f, 0x72, 0x6f, 0x6e, 0x65, 0x2f, 0x62, 0x61, 0x74, 0x6f, 0x6e, 0x2d, 0x73, 0x65, 0x6e, 0x74,
+	0x72, 0x79, 0x2f, 0x70, 0x62, 0x2f, 0x73, 0x65, 0x6e, 0x74, 0x72, 0x79, 0x2f, 0x76, 0x31, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+})
+
+var (
+	file_pb_sentry_v1_dry_run_proto_rawDescOnce sync.Once
+	file_pb_sentry_v1_dry_run_proto_rawDescData []byte
+)
+
+func file_pb_sentry_v1_dry_run_proto_rawDescGZIP() []byte {
+	file_pb_sentry_v1_dry_run_proto_rawDescOnce.Do(func() {
+		file_pb_sentry_v1_dry_run_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pb_sentry_v1_dry_run_proto_rawDesc), len(file_pb_sentry_v1_dry_run_proto_rawDesc)))
+	})
+	return file_pb_sentry_v1_dry_run_proto_rawDescData
+}
+
+var file_pb_sentry_v1_dry_run_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pb_sentry_v1_dry_run_proto_goTypes = []any{
+	(*DryRun)(nil),         // 0: sentry.v1.DryRun
+	(*DryRun_Request)(nil), // 1: sentry.v1.DryRun.Request
+}
+var file_pb_sentry_v1_dry_run_proto_depIdxs = []int32{
+	1, // 0: sentry.v1.DryRun.requests:type_name -> sentry.v1.DryRun.Request
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_pb_sentry_v1_dry_run_proto_init() }
+func file_pb_sentry_v1_dry_run_proto_init() {
+	if File_pb_sentry_v1_dry_run_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pb_sentry_v1_dry_run_proto_rawDesc), len(file_pb_sentry_v1_dry_run_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_pb_sentry_v1_dry_run_proto_goTypes,
+		DependencyIndexes: file_pb_sentry_v1_dry_run_proto_depIdxs,
+		MessageInfos:      file_pb_sentry_v1_dry_run_proto_msgTypes,
+	}.Build()
+	File_pb_sentry_v1_dry_run_proto = out.File
+	file_pb_sentry_v1_dry_run_proto_goTypes = nil
+	file_pb_sentry_v1_dry_run_proto_depIdxs = nil
+}
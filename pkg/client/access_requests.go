@@ -0,0 +1,90 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// ListAccessRequests returns the pending requests to join a team. The endpoint isn't paginated.
+func (c *Client) ListAccessRequests(ctx context.Context, orgID string) ([]AccessRequest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(OrganizationAccessRequestsUrl, orgID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var target []AccessRequest
+	if _, err := c.do(ctx, orgID, req, "list access requests", uhttp.WithJSONResponse(&target)); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// ReviewAccessRequest approves or denies a request to join a team, approving adds the member to the team.
+// Either way the request is gone afterwards.
+func (c *Client) ReviewAccessRequest(ctx context.Context, orgID, requestID string, approve bool) error {
+	body, err := json.Marshal(map[string]bool{"isApproved": approve})
+	if err != nil {
+		return fmt.Errorf("failed to marshal access request review: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.url(OrganizationAccessRequestUrl, orgID, requestID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.do(ctx, orgID, req, "review access request")
+	return err
+}
+
+func (c *Client) ListInviteRequests(ctx context.Context, orgID string, opts PageOptions) (*Page[InviteRequest], error) {
+	return listPage[InviteRequest](ctx, c.orgCredential(ctx, orgID), c.url(OrganizationInviteRequestsUrl, orgID), "list invite requests", opts)
+}
+
+// GetInviteRequest returns a pending invite request by the ID of the member it would create.
+func (c *Client) GetInviteRequest(ctx context.Context, orgID, memberID string) (*InviteRequest, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(OrganizationInviteRequestUrl, orgID, memberID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var target InviteRequest
+	if _, err := c.do(ctx, orgID, req, "get invite request", uhttp.WithJSONResponse(&target)); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// ApproveInviteRequest sends the requested invite, the invitee becomes a pending member.
+func (c *Client) ApproveInviteRequest(ctx context.Context, orgID, memberID string) error {
+	body, err := json.Marshal(map[string]bool{"approve": true})
+	if err != nil {
+		return fmt.Errorf("failed to marshal invite request approval: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.url(OrganizationInviteRequestUrl, orgID, memberID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.do(ctx, orgID, req, "approve invite request")
+	return err
+}
+
+func (c *Client) DenyInviteRequest(ctx context.Context, orgID, memberID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.url(OrganizationInviteRequestUrl, orgID, memberID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, orgID, req, "deny invite request")
+	return err
+}
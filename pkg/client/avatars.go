@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Avatar types, an avatar can only be downloaded when it was uploaded to Sentry.
+const (
+	AvatarTypeUpload   = "upload"
+	AvatarTypeLetter   = "letter_avatar"
+	AvatarTypeGravatar = "gravatar"
+)
+
+// Avatar kinds, each is served from its own path.
+const (
+	OrganizationAvatar = "organization-avatar"
+	TeamAvatar         = "team-avatar"
+	ProjectAvatar      = "project-avatar"
+	UserAvatar         = "avatar"
+)
+
+// GetAvatar downloads an uploaded avatar of the organization, returning its content type and image data. Without an
+// organization the default credential is used.
+func (c *Client) GetAvatar(ctx context.Context, orgID, kind, avatarUUID string) (string, io.ReadCloser, error) {
+	switch kind {
+	case OrganizationAvatar, TeamAvatar, ProjectAvatar, UserAvatar:
+	default:
+		return "", nil, fmt.Errorf("unknown avatar kind %q", kind)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(AvatarUrl, kind, avatarUUID), nil)
+	if err != nil {
+		return "", nil, err
+	}
+
+	cred := c.credential
+	if orgID != "" {
+		cred = c.orgCredential(ctx, orgID)
+	}
+	res, err := send(ctx, cred, req, "get avatar")
+	if err != nil {
+		return "", nil, err
+	}
+
+	return res.Header.Get("Content-Type"), res.Body, nil
+}
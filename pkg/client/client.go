@@ -2,22 +2,240 @@ package client
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
 
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 type Client struct {
+	// baseURL is the Sentry host every request is sent to, BaseUrl unless overridden.
+	baseURL string
+	// recorder records or replays every request when set.
+	recorder *recorder
+	// dryRun skips every request that could change data in Sentry.
+	dryRun bool
+
+	// credential is used for every organization without a token of its own.
+	credential *credential
+
+	// orgTokens holds the organization specific API tokens, keyed by organization slug.
+	orgTokens map[string]string
+	// orgCredentials holds the credentials built from orgTokens, keyed by organization slug.
+	orgCredentials map[string]*credential
+
+	// scimTokens holds the SCIM bearer tokens of organizations provisioned through SCIM, keyed by organization slug.
+	scimTokens map[string]string
+	// scimCredentials holds the credentials built from scimTokens, keyed by organization slug.
+	scimCredentials map[string]*credential
+
+	mtx sync.RWMutex
+	// orgSlugs maps organization IDs to slugs, so calls made with an ID can find their organization specific client.
+	orgSlugs   map[string]string
+	orgsLoaded bool
+	// projectOrgs maps project IDs to their organization, filled as projects are listed or looked up.
+	projectOrgs map[string]string
+	// orgRoles holds the role lists of each organization by ID and slug, refreshed whenever the organization is fetched.
+	orgRoles map[string]*OrganizationRoles
+}
+
+type Option func(*Client)
+
+// WithOrgTokens configures API tokens for organizations that are only reachable with their own credentials,
+// keyed by organization slug. Calls for any other organization use the default API token. A token that fails to list
+// its organizations doesn't fail the listing, see ListOrganizations.
+func WithOrgTokens(tokens map[string]string) Option {
+	return func(c *Client) {
+		c.orgTokens = tokens
+	}
+}
+
+// WithBaseURL sends every request to another Sentry host, such as a self-hosted install or a test server.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = strings.TrimSuffix(baseURL, "/") + "/"
+	}
+}
+
+// credential is the HTTP client for one API token, along with the rate limit budget Sentry tracks for that token.
+type credential struct {
 	*uhttp.BaseHttpClient
+	limiter *rateLimiter
 }
 
-func New(ctx context.Context, apiToken string) (*Client, error) {
-	httpClient, err := uhttp.NewBearerAuth(apiToken).GetClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
+func New(ctx context.Context, apiToken string, opts ...Option) (*Client, error) {
+	c := &Client{
+		baseURL:         BaseUrl,
+		orgCredentials:  map[string]*credential{},
+		scimCredentials: map[string]*credential{},
+		orgSlugs:        map[string]string{},
+		projectOrgs:     map[string]string{},
+		orgRoles:        map[string]*OrganizationRoles{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.recorder != nil {
+		if err := c.recorder.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	cred, err := c.newCredential(ctx, apiToken)
 	if err != nil {
 		return nil, err
 	}
+	c.credential = cred
 
-	return &Client{
+	for slug, token := range c.orgTokens {
+		orgCredential, err := c.newCredential(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		c.orgCredentials[slug] = orgCredential
+	}
+
+	for slug, token := range c.scimTokens {
+		scimCredential, err := c.newCredential(ctx, token)
+		if err != nil {
+			return nil, err
+		}
+		c.scimCredentials[slug] = scimCredential
+	}
+
+	return c, nil
+}
+
+// newCredential builds the credential for a token, routing it through the recorder when one is configured.
+func (c *Client) newCredential(ctx context.Context, token string) (*credential, error) {
+	cred, err := newCredential(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.recorder != nil {
+		cred.HttpClient.Transport = c.recorder.wrap(cred.HttpClient.Transport)
+	}
+
+	return cred, nil
+}
+
+func newCredential(ctx context.Context, token string) (*credential, error) {
+	httpClient, err := uhttp.NewBearerAuth(token).GetClient(ctx, uhttp.WithLogger(true, ctxzap.Extract(ctx)))
+	if err != nil {
+		return nil, err
+	}
+
+	return &credential{
 		BaseHttpClient: uhttp.NewBaseHttpClient(httpClient),
+		limiter:        newRateLimiter(),
 	}, nil
 }
+
+// orgCredential returns the credential for the given organization ID or slug.
+func (c *Client) orgCredential(ctx context.Context, orgID string) *credential {
+	if cred, ok := c.lookupCredential(ctx, c.orgCredentials, orgID); ok {
+		return cred
+	}
+
+	return c.credential
+}
+
+// lookupCredential finds the credential of an organization ID or slug in credentials keyed by slug.
+func (c *Client) lookupCredential(ctx context.Context, credentials map[string]*credential, orgID string) (*credential, bool) {
+	if len(credentials) == 0 {
+		return nil, false
+	}
+
+	if cred, ok := credentials[orgID]; ok {
+		return cred, true
+	}
+
+	slug, ok := c.orgSlug(ctx, orgID)
+	if !ok {
+		return nil, false
+	}
+
+	cred, ok := credentials[slug]
+	return cred, ok
+}
+
+// url builds an absolute URL from one of the relative URL formats.
+func (c *Client) url(format string, args ...any) string {
+	return c.baseURL + fmt.Sprintf(format, args...)
+}
+
+// do sends a request on behalf of an organization, using the credentials configured for it.
+// In dry-run mode only reads are sent.
+func (c *Client) do(ctx context.Context, orgID string, req *http.Request, action string, options ...uhttp.DoOption) (*http.Response, error) {
+	return c.doWith(ctx, c.orgCredential(ctx, orgID), req, action, options...)
+}
+
+func (c *Client) doWith(ctx context.Context, cred *credential, req *http.Request, action string, options ...uhttp.DoOption) (*http.Response, error) {
+	if c.dryRun && req.Method != http.MethodGet {
+		return c.simulate(ctx, req, action)
+	}
+
+	return send(ctx, cred, req, action, options...)
+}
+
+// orgSlug resolves an organization ID to its slug. Provisioning calls can arrive before anything has been
+// listed in this process, so the organizations are listed once to learn the mapping.
+func (c *Client) orgSlug(ctx context.Context, orgID string) (string, bool) {
+	c.mtx.RLock()
+	slug, ok := c.orgSlugs[orgID]
+	loaded := c.orgsLoaded
+	c.mtx.RUnlock()
+	if ok || loaded {
+		return slug, ok
+	}
+
+	_, _, err := c.ListOrganizations(ctx)
+	if err != nil {
+		ctxzap.Extract(ctx).Warn("failed to resolve organization slug", zap.String("org_id", orgID), zap.Error(err))
+		return "", false
+	}
+
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	slug, ok = c.orgSlugs[orgID]
+	return slug, ok
+}
+
+func (c *Client) rememberOrgs(orgs []Organization) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for _, org := range orgs {
+		c.orgSlugs[org.ID] = org.Slug
+	}
+	c.orgsLoaded = true
+}
+
+func (c *Client) rememberProjects(orgID string, projects []Project) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	for _, project := range projects {
+		c.projectOrgs[project.ID] = orgID
+	}
+}
+
+func (c *Client) projectOrg(projectID string) (string, bool) {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+	orgID, ok := c.projectOrgs[projectID]
+	return orgID, ok
+}
+
+func (c *Client) rememberOrgRoles(org *Organization) {
+	roles := &OrganizationRoles{OrgRoles: org.OrgRoleList, TeamRoles: org.TeamRoleList}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.orgRoles[org.ID] = roles
+	c.orgRoles[org.Slug] = roles
+}
@@ -0,0 +1,92 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// WithDryRun makes every mutating call log the request it would send and report success without sending it.
+// Reads still reach Sentry, so idempotency checks behave as they would for real.
+func WithDryRun(dryRun bool) Option {
+	return func(c *Client) {
+		c.dryRun = dryRun
+	}
+}
+
+// SimulatedRequest is a mutating request that was not sent because of dry-run mode.
+type SimulatedRequest struct {
+	Action string
+	Method string
+	URL    string
+	Body   string
+}
+
+// Simulations collects the requests skipped in dry-run mode by the calls made with a context from WithSimulations.
+type Simulations struct {
+	mtx      sync.Mutex
+	requests []SimulatedRequest
+}
+
+// Requests returns the skipped requests in the order they were made.
+func (s *Simulations) Requests() []SimulatedRequest {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]SimulatedRequest(nil), s.requests...)
+}
+
+func (s *Simulations) add(req SimulatedRequest) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.requests = append(s.requests, req)
+}
+
+type simulationsKey struct{}
+
+// WithSimulations returns a context that collects the requests skipped in dry-run mode.
+func WithSimulations(ctx context.Context) (context.Context, *Simulations) {
+	s := &Simulations{}
+	return context.WithValue(ctx, simulationsKey{}, s), s
+}
+
+// simulate logs a mutating request instead of sending it, answering as if Sentry accepted it.
+func (c *Client) simulate(ctx context.Context, req *http.Request, action string) (*http.Response, error) {
+	var body string
+	if req.GetBody != nil {
+		if r, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(r)
+			body = string(data)
+		}
+	}
+
+	simulated := SimulatedRequest{
+		Action: action,
+		Method: req.Method,
+		URL:    req.URL.String(),
+		Body:   body,
+	}
+	ctxzap.Extract(ctx).Info("dry run: not sending request",
+		zap.String("action", simulated.Action),
+		zap.String("method", simulated.Method),
+		zap.String("url", simulated.URL),
+		zap.String("body", simulated.Body),
+	)
+	if s, ok := ctx.Value(simulationsKey{}).(*Simulations); ok {
+		s.add(simulated)
+	}
+
+	return &http.Response{
+		Status:     "202 Accepted",
+		StatusCode: http.StatusAccepted,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     http.Header{},
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,195 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// APIError is a failed response from the Sentry API.
+//
+// Sentry reports failures as JSON, either as {"detail": "..."} or as validation errors keyed by
+// request field, e.g. {"email": ["Enter a valid email address."]}. APIError implements GRPCStatus
+// so the SDK can tell a missing object from a missing scope or a rate limit.
+type APIError struct {
+	// Action describes the call that failed, e.g. "add member to organization".
+	Action     string
+	StatusCode int
+	Status     string
+	Detail     string
+	// FieldErrors are validation errors keyed by request field.
+	FieldErrors map[string][]string
+
+	// err is the error returned by the HTTP client, it carries the rate limit details of the response.
+	err error
+}
+
+func newAPIError(action string, res *http.Response, err error) *APIError {
+	apiErr := &APIError{
+		Action:     action,
+		StatusCode: res.StatusCode,
+		Status:     res.Status,
+		err:        err,
+	}
+
+	body, readErr := io.ReadAll(res.Body)
+	if readErr != nil || len(body) == 0 {
+		return apiErr
+	}
+	apiErr.parseBody(body)
+
+	return apiErr
+}
+
+func (e *APIError) parseBody(body []byte) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		// Some endpoints answer with a bare list of messages.
+		var messages []string
+		if err := json.Unmarshal(body, &messages); err == nil {
+			e.Detail = strings.Join(messages, " ")
+		}
+		return
+	}
+
+	for key, raw := range fields {
+		if key == "detail" {
+			e.Detail = parseDetail(raw)
+			continue
+		}
+		// SCIM errors carry their schema and status next to the detail.
+		if key == "schemas" || key == "status" {
+			continue
+		}
+
+		var messages []string
+		if err := json.Unmarshal(raw, &messages); err != nil {
+			var message string
+			if err := json.Unmarshal(raw, &message); err != nil {
+				continue
+			}
+			messages = []string{message}
+		}
+		if e.FieldErrors == nil {
+			e.FieldErrors = make(map[string][]string)
+		}
+		e.FieldErrors[key] = messages
+	}
+}
+
+// parseDetail reads the detail field, which is either a string or an object with a message.
+func parseDetail(raw json.RawMessage) string {
+	var detail string
+	if err := json.Unmarshal(raw, &detail); err == nil {
+		return detail
+	}
+
+	var structured struct {
+		Message string `json:"message"`
+		Code    string `json:"code"`
+	}
+	if err := json.Unmarshal(raw, &structured); err == nil {
+		if structured.Message != "" {
+			return structured.Message
+		}
+		return structured.Code
+	}
+
+	return string(raw)
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("failed to %s: %s", e.Action, e.Status)
+	if e.Detail != "" {
+		msg += ": " + e.Detail
+	}
+
+	keys := make([]string, 0, len(e.FieldErrors))
+	for key := range e.FieldErrors {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		msg += fmt.Sprintf(": %s: %s", key, strings.Join(e.FieldErrors[key], " "))
+	}
+
+	return msg
+}
+
+func (e *APIError) Unwrap() error {
+	return e.err
+}
+
+// Code maps the HTTP status of the response to a gRPC code.
+func (e *APIError) Code() codes.Code {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusRequestTimeout:
+		return codes.DeadlineExceeded
+	case http.StatusTooManyRequests:
+		return codes.Unavailable
+	case http.StatusNotImplemented:
+		return codes.Unimplemented
+	}
+
+	if e.StatusCode >= 500 {
+		return codes.Unavailable
+	}
+
+	return codes.Unknown
+}
+
+// GRPCStatus returns the status for the error, keeping any details, such as rate limit data,
+// attached by the HTTP client.
+func (e *APIError) GRPCStatus() *status.Status {
+	p := status.New(e.Code(), e.Error()).Proto()
+	if wrapped, ok := status.FromError(e.err); ok && e.err != nil {
+		p.Details = wrapped.Proto().GetDetails()
+	}
+
+	return status.FromProto(p)
+}
+
+// IsNotFound reports whether err is a Sentry API error for a missing object.
+func IsNotFound(err error) bool {
+	return hasStatusCode(err, http.StatusNotFound)
+}
+
+// IsForbidden reports whether err is a Sentry API error for a call the token, or the organization's plan, doesn't allow.
+func IsForbidden(err error) bool {
+	return hasStatusCode(err, http.StatusForbidden)
+}
+
+// IsConflict reports whether err is a Sentry API error for an object that already exists.
+func IsConflict(err error) bool {
+	return hasStatusCode(err, http.StatusConflict)
+}
+
+// IsManagedByIdentityProvider reports whether err is Sentry refusing to change a member or team that is
+// provisioned by the organization's identity provider through SCIM.
+func IsManagedByIdentityProvider(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusForbidden &&
+		strings.Contains(apiErr.Detail, "identity provider")
+}
+
+func hasStatusCode(err error, statusCode int) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == statusCode
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		body        string
+		wantCode    codes.Code
+		wantDetail  string
+		wantFields  map[string][]string
+		wantMessage string
+	}{
+		{
+			name:        "detail",
+			statusCode:  http.StatusNotFound,
+			body:        `{"detail": "The requested resource does not exist"}`,
+			wantCode:    codes.NotFound,
+			wantDetail:  "The requested resource does not exist",
+			wantMessage: "failed to get member: 404 Not Found: The requested resource does not exist",
+		},
+		{
+			name:        "structured detail",
+			statusCode:  http.StatusForbidden,
+			body:        `{"detail": {"code": "superuser-required", "message": "Superuser required"}}`,
+			wantCode:    codes.PermissionDenied,
+			wantDetail:  "Superuser required",
+			wantMessage: "failed to get member: 403 Forbidden: Superuser required",
+		},
+		{
+			name:       "field errors",
+			statusCode: http.StatusBadRequest,
+			body:       `{"email": ["Enter a valid email address."], "orgRole": "Invalid role"}`,
+			wantCode:   codes.InvalidArgument,
+			wantFields: map[string][]string{
+				"email":   {"Enter a valid email address."},
+				"orgRole": {"Invalid role"},
+			},
+			wantMessage: "failed to get member: 400 Bad Request: email: Enter a valid email address.: orgRole: Invalid role",
+		},
+		{
+			name:        "conflict",
+			statusCode:  http.StatusConflict,
+			body:        `["This member is already in the team"]`,
+			wantCode:    codes.AlreadyExists,
+			wantDetail:  "This member is already in the team",
+			wantMessage: "failed to get member: 409 Conflict: This member is already in the team",
+		},
+		{
+			name:        "rate limited",
+			statusCode:  http.StatusTooManyRequests,
+			wantCode:    codes.Unavailable,
+			wantMessage: "failed to get member: 429 Too Many Requests",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := &http.Response{
+				StatusCode: tt.statusCode,
+				Status:     fmt.Sprintf("%d %s", tt.statusCode, http.StatusText(tt.statusCode)),
+				Body:       io.NopCloser(strings.NewReader(tt.body)),
+			}
+
+			apiErr := newAPIError("get member", res, nil)
+			assert.Equal(t, tt.wantDetail, apiErr.Detail)
+			assert.Equal(t, tt.wantFields, apiErr.FieldErrors)
+			assert.Equal(t, tt.wantMessage, apiErr.Error())
+
+			wrapped := fmt.Errorf("baton-sentry: %w", apiErr)
+			assert.Equal(t, tt.wantCode, status.Code(wrapped))
+			assert.Equal(t, tt.statusCode == http.StatusNotFound, IsNotFound(wrapped))
+			assert.Equal(t, tt.statusCode == http.StatusConflict, IsConflict(wrapped))
+			assert.Equal(t, tt.statusCode == http.StatusForbidden, IsForbidden(wrapped))
+		})
+	}
+}
+
+func TestAPIErrorKeepsStatusDetails(t *testing.T) {
+	st, err := status.New(codes.Unavailable, "429 Too Many Requests").WithDetails(&v2.RateLimitDescription{Limit: 40, Remaining: 0})
+	assert.NoError(t, err)
+
+	res := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Status:     "429 Too Many Requests",
+		Body:       io.NopCloser(strings.NewReader("")),
+	}
+	apiErr := newAPIError("list teams", res, errors.Join(st.Err()))
+
+	assert.Len(t, apiErr.GRPCStatus().Details(), 1)
+}
+
+func TestIsManagedByIdentityProvider(t *testing.T) {
+	newErr := func(statusCode int, body string) error {
+		res := &http.Response{StatusCode: statusCode, Status: http.StatusText(statusCode), Body: io.NopCloser(strings.NewReader(body))}
+		return fmt.Errorf("wrapped: %w", newAPIError("delete member", res, nil))
+	}
+
+	assert.True(t, IsManagedByIdentityProvider(newErr(http.StatusForbidden, `{"detail": "This user is managed through your organization's identity provider."}`)))
+	assert.False(t, IsManagedByIdentityProvider(newErr(http.StatusForbidden, `{"detail": "You do not have permission to perform this action."}`)))
+	assert.False(t, IsManagedByIdentityProvider(newErr(http.StatusBadRequest, `{"detail": "identity provider"}`)))
+	assert.False(t, IsManagedByIdentityProvider(errors.New("identity provider")))
+}
@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// ListMemberExternalUsers returns the external identities linked to an organization member.
+// Sentry has no endpoint listing them directly, they are only returned alongside the organization members: the list
+// is searched for the member's ID.
+func (c *Client) ListMemberExternalUsers(ctx context.Context, orgID, memberID string) ([]ExternalUser, error) {
+	q := url.Values{}
+	q.Set("query", "id:"+memberID)
+	q.Set("expand", "externalUsers")
+	membersURL := c.url(OrganizationMembersUrl, orgID) + "?" + q.Encode()
+
+	page, err := listPage[OrganizationMember](ctx, c.orgCredential(ctx, orgID), membersURL, "list organization members", PageOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, member := range page.Items {
+		if member.ID == memberID {
+			return member.ExternalUsers, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// https://docs.sentry.io/api/integrations/delete-an-external-user/
+func (c *Client) DeleteExternalUser(ctx context.Context, orgID, externalUserID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.url(ExternalUserUrl, orgID, externalUserID), nil)
+	if err != nil {
+		return err
+	}
+
+	_, err = c.do(ctx, orgID, req, "delete external user")
+	return err
+}
@@ -1,26 +1,145 @@
 package client
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"github.com/peterhellberg/link"
 	"go.uber.org/zap"
 )
 
-func logBody(ctx context.Context, bodyCloser io.ReadCloser) {
-	defer bodyCloser.Close()
-	l := ctxzap.Extract(ctx)
-	body := make([]byte, 1024*1024)
-	n, err := bodyCloser.Read(body)
+// send performs the request and turns failed responses into an *APIError.
+// action describes the call for error messages, e.g. "list teams".
+//
+// Requests wait while the rate limit budget of the credential is low, and rate limited
+// requests are retried once the window allows it.
+func send(ctx context.Context, cred *credential, req *http.Request, action string, options ...uhttp.DoOption) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := cred.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		res, err := cred.do(req, options...)
+		if res != nil {
+			cred.limiter.update(res)
+		}
+
+		if res != nil && res.StatusCode == http.StatusTooManyRequests && attempt < maxRateLimitRetries {
+			retryReq, canRetry := rewind(req)
+			delay := cred.limiter.retryDelay(res)
+			if canRetry && delay <= maxRateLimitWait {
+				ctxzap.Extract(ctx).Debug("sentry rate limit exceeded, retrying",
+					zap.String("action", action),
+					zap.Int("attempt", attempt+1),
+					zap.Duration("delay", delay),
+				)
+				if err := cred.limiter.sleep(ctx, delay); err != nil {
+					return nil, err
+				}
+				req = retryReq
+				continue
+			}
+		}
+
+		return checkResponse(ctx, res, err, action)
+	}
+}
+
+type withoutCacheKey struct{}
+
+// WithoutCache returns a context whose reads skip the SDK's HTTP cache. Updates that write back what they read
+// use it, a cached copy can be up to an hour old and writing it back would undo the changes made since.
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, withoutCacheKey{}, true)
+}
+
+// do performs a single request, reads made with a context from WithoutCache go around the cache.
+func (c *credential) do(req *http.Request, options ...uhttp.DoOption) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Context().Value(withoutCacheKey{}) == nil {
+		return c.Do(req, options...)
+	}
+
+	res, err := c.HttpClient.Do(req)
 	if err != nil {
-		l.Error("error reading response body", zap.Error(err))
-		return
+		return nil, err
 	}
-	l.Info("response body: ", zap.String("body", string(body[:n])))
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	// Failed responses are turned into an *APIError from the body by checkResponse.
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		return res, nil
+	}
+
+	wrapper := &uhttp.WrapperResponse{
+		Header:     res.Header,
+		Body:       body,
+		Status:     res.Status,
+		StatusCode: res.StatusCode,
+	}
+	var errs []error
+	for _, option := range options {
+		if err := option(wrapper); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return res, errors.Join(errs...)
+}
+
+func checkResponse(ctx context.Context, res *http.Response, err error, action string) (*http.Response, error) {
+	if res == nil {
+		if err != nil {
+			return nil, fmt.Errorf("failed to %s: %w", action, err)
+		}
+		return nil, fmt.Errorf("failed to %s: no response", action)
+	}
+
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		apiErr := newAPIError(action, res, err)
+		ctxzap.Extract(ctx).Debug("sentry api error",
+			zap.String("action", action),
+			zap.Int("status_code", apiErr.StatusCode),
+			zap.String("detail", apiErr.Detail),
+		)
+		return res, apiErr
+	}
+
+	if err != nil {
+		return res, fmt.Errorf("failed to %s: %w", action, err)
+	}
+
+	return res, nil
+}
+
+// rewind returns a copy of req that can be sent again, or false when its body can't be replayed.
+func rewind(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req, true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	retryReq := req.Clone(req.Context())
+	retryReq.Body = body
+
+	return retryReq, true
 }
 
 // https://docs.sentry.io/api/pagination/
@@ -49,35 +168,45 @@ func NextCursor(res *http.Response) string {
 }
 
 func FindUserOrgID(ctx context.Context, client *Client, userID string) (string, error) {
-	allOrgs := []Organization{}
-	cursor := ""
-	for {
-		organizations, res, _, err := client.ListOrganizations(ctx, cursor)
-		defer res.Body.Close()
+	allOrgs, _, err := client.ListOrganizations(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	for _, org := range allOrgs {
+		_, _, err := client.GetOrganizationMember(ctx, org.ID, userID)
 		if err != nil {
-			return "", fmt.Errorf("failed to list organizations: %w", err)
+			continue
 		}
-		allOrgs = append(allOrgs, organizations...)
+		return org.ID, nil
+	}
 
-		if !HasNextPage(res) {
-			break
-		}
-		cursor = NextCursor(res)
+	return "", fmt.Errorf("user with ID %s not found in any organization", userID)
+}
+
+// FindProjectOrgID returns the organization of a project, project IDs are unique across organizations. Projects
+// already listed are not looked up again.
+func FindProjectOrgID(ctx context.Context, client *Client, projectID string) (string, error) {
+	if orgID, ok := client.projectOrg(projectID); ok {
+		return orgID, nil
+	}
+
+	allOrgs, _, err := client.ListOrganizations(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list organizations: %w", err)
 	}
 
-	userOrgID := ""
 	for _, org := range allOrgs {
-		_, res, err := client.GetOrganizationMember(ctx, org.ID, userID)
-		defer res.Body.Close()
-		if err != nil {
+		_, _, err := client.GetProject(ctx, org.ID, projectID)
+		if IsNotFound(err) {
 			continue
 		}
-		userOrgID = org.ID
-		break
+		if err != nil {
+			return "", fmt.Errorf("failed to get project %s of organization %s: %w", projectID, org.ID, err)
+		}
+		client.rememberProjects(org.ID, []Project{{ID: projectID}})
+		return org.ID, nil
 	}
 
-	if userOrgID == "" {
-		return "", fmt.Errorf("user with ID %s not found in any organization", userID)
-	}
-	return userOrgID, nil
+	return "", fmt.Errorf("project with ID %s not found in any organization", projectID)
 }
@@ -0,0 +1,56 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// docs: https://docs.sentry.io/api/events/
+
+// IssueSearchPeriod is how far back issue searches look, Sentry only searches the last 14 days by default and keeps
+// 90 days of events. Issues not seen within the period aren't found.
+const IssueSearchPeriod = "90d"
+
+// maxBulkIssues is the largest number of issues Sentry updates in one bulk request.
+const maxBulkIssues = 100
+
+// https://docs.sentry.io/api/events/list-an-organizations-issues/
+func (c *Client) ListIssues(ctx context.Context, orgID, query string, opts PageOptions) (*Page[Issue], error) {
+	q := url.Values{}
+	q.Set("query", query)
+	q.Set("statsPeriod", IssueSearchPeriod)
+
+	return listPage[Issue](ctx, c.orgCredential(ctx, orgID), c.url(OrganizationIssuesUrl, orgID)+"?"+q.Encode(), "list issues", opts)
+}
+
+// AssignIssues assigns the issues to an actor, "user:<user id>" or "team:<team id>".
+// https://docs.sentry.io/api/events/bulk-mutate-an-organizations-issues/
+func (c *Client) AssignIssues(ctx context.Context, orgID string, issueIDs []string, assignee string) error {
+	body, err := json.Marshal(map[string]string{"assignedTo": assignee})
+	if err != nil {
+		return fmt.Errorf("failed to marshal issue update: %w", err)
+	}
+
+	for start := 0; start < len(issueIDs); start += maxBulkIssues {
+		q := url.Values{}
+		for _, id := range issueIDs[start:min(start+maxBulkIssues, len(issueIDs))] {
+			q.Add("id", id)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.url(OrganizationIssuesUrl, orgID)+"?"+q.Encode(), bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		if _, err := c.do(ctx, orgID, req, "assign issues"); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
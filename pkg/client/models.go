@@ -19,6 +19,34 @@ type Organization struct {
 	Require2FA                 bool               `json:"require2FA"`
 	Slug                       string             `json:"slug"`
 	Status                     OrganizationStatus `json:"status"`
+	// OpenMembership lets every member join any team. It, the trusted relays and the role lists are only present
+	// when a single organization is fetched.
+	OpenMembership bool               `json:"openMembership,omitempty"`
+	TrustedRelays  []TrustedRelay     `json:"trustedRelays,omitempty"`
+	OrgRoleList    []OrganizationRole `json:"orgRoleList,omitempty"`
+	TeamRoleList   []TeamRole         `json:"teamRoleList,omitempty"`
+}
+
+// TrustedRelay is a Relay allowed to forward events on behalf of the organization, identified by its public key.
+type TrustedRelay struct {
+	Name         string     `json:"name"`
+	PublicKey    string     `json:"publicKey"`
+	Description  string     `json:"description"`
+	Created      time.Time  `json:"created"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+}
+
+// AuthProvider is the SSO configuration of an organization.
+type AuthProvider struct {
+	ID                string `json:"id"`
+	ProviderName      string `json:"provider_name"`
+	PendingLinksCount int    `json:"pending_links_count"`
+	LoginURL          string `json:"login_url"`
+	// DefaultRole is the organization role given to members provisioned through SSO.
+	DefaultRole string `json:"default_role"`
+	// RequireLink is set when members must sign in through SSO.
+	RequireLink bool `json:"require_link"`
+	ScimEnabled bool `json:"scim_enabled"`
 }
 
 type Avatar struct {
@@ -48,6 +76,8 @@ type OrganizationMember struct {
 	DateCreated  time.Time   `json:"dateCreated"`
 	InviteStatus string      `json:"inviteStatus"`
 	InviterName  string      `json:"inviterName"`
+	// Only present when the members are listed with expand=externalUsers.
+	ExternalUsers []ExternalUser `json:"externalUsers,omitempty"`
 }
 
 type User struct {
@@ -220,6 +250,51 @@ type DetailedMember struct {
 	TeamRoleList []TeamRole          `json:"teamRoleList"`
 }
 
+// OrganizationMember returns the member in the shape returned by the organization members list.
+func (m DetailedMember) OrganizationMember() OrganizationMember {
+	member := OrganizationMember{
+		ID:           m.ID,
+		Email:        m.Email,
+		Name:         m.Name,
+		OrgRole:      m.OrgRole,
+		Pending:      m.Pending,
+		Expired:      m.Expired,
+		Flags:        MemberFlags(m.Flags),
+		DateCreated:  m.DateCreated,
+		InviteStatus: m.InviteStatus,
+		InviterName:  m.InviterName,
+	}
+
+	if m.User != nil {
+		emails := make([]UserEmail, 0, len(m.User.Emails))
+		for _, email := range m.User.Emails {
+			emails = append(emails, UserEmail(email))
+		}
+		member.User = &User{
+			ID:              m.User.ID,
+			Name:            m.User.Name,
+			Username:        m.User.Username,
+			Email:           m.User.Email,
+			AvatarURL:       m.User.AvatarURL,
+			IsActive:        m.User.IsActive,
+			HasPasswordAuth: m.User.HasPasswordAuth,
+			IsManaged:       m.User.IsManaged,
+			DateJoined:      m.User.DateJoined,
+			LastLogin:       m.User.LastLogin,
+			Has2FA:          m.User.Has2FA,
+			LastActive:      m.User.LastActive,
+			IsSuperuser:     m.User.IsSuperuser,
+			IsStaff:         m.User.IsStaff,
+			Experiments:     m.User.Experiments,
+			Emails:          emails,
+			Avatar:          Avatar(m.User.Avatar),
+			CanReset2FA:     m.User.CanReset2FA,
+		}
+	}
+
+	return member
+}
+
 type DetailedMemberUser struct {
 	ID              string                `json:"id"`
 	Name            string                `json:"name"`
@@ -376,6 +451,57 @@ type DetailedProject struct {
 	HighlightPreset                  HighlightPreset        `json:"highlightPreset"`
 }
 
+// Project returns the project in the shape returned by the organization projects list.
+func (p DetailedProject) Project() Project {
+	project := Project{
+		ID:                         p.ID,
+		Slug:                       p.Slug,
+		Name:                       p.Name,
+		IsBookmarked:               p.IsBookmarked,
+		IsMember:                   p.IsMember,
+		Features:                   p.Features,
+		FirstTransactionEvent:      p.FirstTransactionEvent,
+		Access:                     p.Access,
+		HasAccess:                  p.HasAccess,
+		HasMinifiedStackTrace:      p.HasMinifiedStackTrace,
+		HasMonitors:                p.HasMonitors,
+		HasProfiles:                p.HasProfiles,
+		HasReplays:                 p.HasReplays,
+		HasFlags:                   p.HasFlags,
+		HasFeedbacks:               p.HasFeedbacks,
+		HasNewFeedbacks:            p.HasNewFeedbacks,
+		HasSessions:                p.HasSessions,
+		HasInsightsHttp:            p.HasInsightsHttp,
+		HasInsightsDb:              p.HasInsightsDb,
+		HasInsightsAssets:          p.HasInsightsAssets,
+		HasInsightsAppStart:        p.HasInsightsAppStart,
+		HasInsightsScreenLoad:      p.HasInsightsScreenLoad,
+		HasInsightsVitals:          p.HasInsightsVitals,
+		HasInsightsCaches:          p.HasInsightsCaches,
+		HasInsightsQueues:          p.HasInsightsQueues,
+		HasInsightsLlmMonitoring:   p.HasInsightsLlmMonitoring,
+		HasInsightsAgentMonitoring: p.HasInsightsAgentMonitoring,
+		IsInternal:                 p.IsInternal,
+		IsPublic:                   p.IsPublic,
+		Avatar:                     p.Avatar,
+		Color:                      p.Color,
+		Status:                     p.Status,
+	}
+
+	if p.Platform != "" {
+		platform := p.Platform
+		project.Platform = &platform
+	}
+	if dateCreated, err := time.Parse(time.RFC3339, p.DateCreated); err == nil {
+		project.DateCreated = dateCreated
+	}
+	if firstEvent, err := time.Parse(time.RFC3339, p.FirstEvent); err == nil {
+		project.FirstEvent = &firstEvent
+	}
+
+	return project
+}
+
 type ProjectTeam struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
@@ -422,6 +548,8 @@ type Plugin struct {
 	Features              []string               `json:"features"`
 	FeatureDescriptions   []FeatureDescription   `json:"featureDescriptions"`
 	ResourceLinks         []ResourceLink         `json:"resourceLinks"`
+	// Config is only present when a single plugin is fetched.
+	Config []PluginConfigField `json:"config,omitempty"`
 }
 
 type Author struct {
@@ -460,3 +588,185 @@ type AddOrganizationMemberBody struct {
 	// "owner", "manager", "member", "billing"
 	OrgRole string `json:"orgRole,omitempty"`
 }
+
+// ExternalUser links a Sentry user to their identity in an integration such as GitHub or Slack.
+type ExternalUser struct {
+	ID            string `json:"id"`
+	Provider      string `json:"provider"`
+	ExternalName  string `json:"externalName"`
+	ExternalID    string `json:"externalId"`
+	UserID        string `json:"userId"`
+	IntegrationID string `json:"integrationId"`
+}
+
+type Issue struct {
+	ID         string `json:"id"`
+	ShortID    string `json:"shortId"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	AssignedTo *Actor `json:"assignedTo"`
+}
+
+// Actor is a user or a team as Sentry returns them, for example as issue assignees or monitor owners.
+type Actor struct {
+	// Type is either "user" or "team".
+	Type  string `json:"type"`
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// String returns the actor in the "user:<user id>" or "team:<team id>" form Sentry accepts on updates.
+func (a Actor) String() string {
+	return a.Type + ":" + a.ID
+}
+
+// AlertRule holds the fields shared by metric and issue alert rules.
+type AlertRule struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	DateCreated time.Time `json:"dateCreated"`
+	// Owner is an actor, "user:<user id>" or "team:<team id>", or nil when nobody owns the rule.
+	Owner    *string  `json:"owner"`
+	Projects []string `json:"projects"`
+}
+
+// Monitor is a cron monitor.
+type Monitor struct {
+	ID          string         `json:"id"`
+	Slug        string         `json:"slug"`
+	Name        string         `json:"name"`
+	Status      string         `json:"status"`
+	IsMuted     bool           `json:"isMuted"`
+	DateCreated time.Time      `json:"dateCreated"`
+	Project     MonitorProject `json:"project"`
+	Owner       *Actor         `json:"owner"`
+}
+
+type MonitorProject struct {
+	ID   string `json:"id"`
+	Slug string `json:"slug"`
+	Name string `json:"name"`
+}
+
+// UptimeMonitor checks that a URL responds, it belongs to a project.
+type UptimeMonitor struct {
+	ID          string `json:"id"`
+	ProjectSlug string `json:"projectSlug"`
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	URL         string `json:"url"`
+	Owner       *Actor `json:"owner"`
+}
+
+// PluginConfigField is a setting of a plugin, secret settings come back with their value masked.
+type PluginConfigField struct {
+	Name  string `json:"name"`
+	Label string `json:"label"`
+	Type  string `json:"type"`
+	Value any    `json:"value"`
+}
+
+// ServiceHook posts the events of a project to a URL, signed with the hook's secret.
+type ServiceHook struct {
+	ID          string    `json:"id"`
+	URL         string    `json:"url"`
+	Status      string    `json:"status"`
+	Events      []string  `json:"events"`
+	DateCreated time.Time `json:"dateCreated"`
+}
+
+type Dashboard struct {
+	ID          string    `json:"id"`
+	Title       string    `json:"title"`
+	DateCreated time.Time `json:"dateCreated"`
+	// Permissions is nil for dashboards that never had edit access restricted, everyone can edit them.
+	Permissions *DashboardPermissions `json:"permissions"`
+}
+
+type DashboardPermissions struct {
+	IsEditableByEveryone bool `json:"isEditableByEveryone"`
+	// TeamsWithEditAccess only matters when IsEditableByEveryone is false, the dashboard's creator and
+	// organization managers can always edit it.
+	TeamsWithEditAccess []int `json:"teamsWithEditAccess"`
+}
+
+// EditPermissions returns the edit access of the dashboard, filling in the default for dashboards without any.
+func (d Dashboard) EditPermissions() DashboardPermissions {
+	if d.Permissions == nil {
+		return DashboardPermissions{IsEditableByEveryone: true, TeamsWithEditAccess: []int{}}
+	}
+	return *d.Permissions
+}
+
+type ProjectOwnership struct {
+	// Raw holds the rules in Sentry's ownership syntax, one "<type>:<pattern> <owner>..." rule per line.
+	Raw                string     `json:"raw"`
+	FallThrough        bool       `json:"fallthrough"`
+	AutoAssignment     string     `json:"autoAssignment"`
+	CodeownersAutoSync bool       `json:"codeownersAutoSync"`
+	IsActive           bool       `json:"isActive"`
+	DateCreated        *time.Time `json:"dateCreated"`
+	LastUpdated        *time.Time `json:"lastUpdated"`
+}
+
+type CodeOwners struct {
+	ID            string `json:"id"`
+	Raw           string `json:"raw"`
+	Provider      string `json:"provider"`
+	CodeMappingID string `json:"codeMappingId"`
+	// OwnershipSyntax is Raw translated to Sentry's ownership syntax, with the code host's users and teams
+	// replaced by Sentry ones. Only present when listed with expand=ownershipSyntax.
+	OwnershipSyntax string    `json:"ownershipSyntax"`
+	DateCreated     time.Time `json:"dateCreated"`
+	DateUpdated     time.Time `json:"dateUpdated"`
+}
+
+// AccessRequest is a member's pending request to join a team. Requester is set when someone else asked for
+// the member to be added.
+type AccessRequest struct {
+	ID        string             `json:"id"`
+	Member    OrganizationMember `json:"member"`
+	Team      Team               `json:"team"`
+	Requester *User              `json:"requester"`
+}
+
+// InviteRequest is a pending invite that needs a manager's approval, either asked for by a member who can't
+// invite (InviteStatus "requested_to_be_invited") or by the invitee (InviteStatus "requested_to_join").
+type InviteRequest struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	OrgRole      string    `json:"orgRole"`
+	InviteStatus string    `json:"inviteStatus"`
+	InviterName  string    `json:"inviterName"`
+	Teams        []string  `json:"teams"`
+	DateCreated  time.Time `json:"dateCreated"`
+}
+
+// SCIMUser is an organization member as the SCIM endpoints see it, its ID is the member ID.
+type SCIMUser struct {
+	Schemas  []string    `json:"schemas"`
+	ID       string      `json:"id,omitempty"`
+	UserName string      `json:"userName"`
+	Emails   []SCIMEmail `json:"emails,omitempty"`
+	Active   bool        `json:"active"`
+	// SentryOrgRole is the organization role, the organization's default role when empty.
+	SentryOrgRole string `json:"sentryOrgRole,omitempty"`
+}
+
+type SCIMEmail struct {
+	Primary bool   `json:"primary"`
+	Value   string `json:"value"`
+	Type    string `json:"type"`
+}
+
+type SCIMPatch struct {
+	Schemas    []string        `json:"schemas"`
+	Operations []SCIMOperation `json:"Operations"`
+}
+
+type SCIMOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
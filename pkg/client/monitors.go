@@ -0,0 +1,84 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// docs: https://docs.sentry.io/api/crons/
+
+// https://docs.sentry.io/api/crons/retrieve-monitors-for-an-organization/
+func (c *Client) ListMonitors(ctx context.Context, orgID string, opts PageOptions) (*Page[Monitor], error) {
+	return listPage[Monitor](ctx, c.orgCredential(ctx, orgID), c.url(OrganizationMonitorsUrl, orgID), "list monitors", opts)
+}
+
+// https://docs.sentry.io/api/crons/retrieve-a-monitor/
+func (c *Client) GetMonitor(ctx context.Context, orgID, monitorSlug string) (*Monitor, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(OrganizationMonitorUrl, orgID, monitorSlug), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var target Monitor
+	if _, err := c.do(ctx, orgID, req, "get monitor", uhttp.WithJSONResponse(&target)); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// SetMonitorOwner changes the owner of a cron monitor, an actor string or "" to clear it.
+// https://docs.sentry.io/api/crons/update-a-monitor/
+func (c *Client) SetMonitorOwner(ctx context.Context, orgID, monitorSlug, owner string) error {
+	return c.setMonitorOwner(ctx, orgID, c.url(OrganizationMonitorUrl, orgID, monitorSlug), "monitor", owner)
+}
+
+func (c *Client) ListUptimeMonitors(ctx context.Context, orgID string, opts PageOptions) (*Page[UptimeMonitor], error) {
+	return listPage[UptimeMonitor](ctx, c.orgCredential(ctx, orgID), c.url(OrganizationUptimeMonitorsUrl, orgID), "list uptime monitors", opts)
+}
+
+func (c *Client) GetUptimeMonitor(ctx context.Context, orgID, projectID, monitorID string) (*UptimeMonitor, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(ProjectUptimeMonitorUrl, orgID, projectID, monitorID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var target UptimeMonitor
+	if _, err := c.do(ctx, orgID, req, "get uptime monitor", uhttp.WithJSONResponse(&target)); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// SetUptimeMonitorOwner changes the owner of an uptime monitor, an actor string or "" to clear it.
+func (c *Client) SetUptimeMonitorOwner(ctx context.Context, orgID, projectID, monitorID, owner string) error {
+	return c.setMonitorOwner(ctx, orgID, c.url(ProjectUptimeMonitorUrl, orgID, projectID, monitorID), "uptime monitor", owner)
+}
+
+// setMonitorOwner changes the owner of a monitor. Unlike alert rules, monitors accept partial updates.
+func (c *Client) setMonitorOwner(ctx context.Context, orgID, monitorURL, kind, owner string) error {
+	var value *string
+	if owner != "" {
+		value = &owner
+	}
+
+	body, err := json.Marshal(map[string]*string{"owner": value})
+	if err != nil {
+		return fmt.Errorf("failed to marshal %s: %w", kind, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, monitorURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.do(ctx, orgID, req, "update "+kind)
+	return err
+}
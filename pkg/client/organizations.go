@@ -6,106 +6,190 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
+	"sort"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 // docs: https://docs.sentry.io/api/organizations/
 
-func (c *Client) ListOrganizations(ctx context.Context, cursor string) ([]Organization, *http.Response, *v2.RateLimitDescription, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, OrganizationsUrl, nil)
-	if err != nil {
-		return nil, nil, nil, err
+// OrgRoleOwner is the organization role with full control, every organization needs at least one.
+const OrgRoleOwner = "owner"
+
+// OrgRoleMember is the organization role with the least access, every member holds at least this one.
+const OrgRoleMember = "member"
+
+// TeamRoleAdmin is the team role that manages the team's members and projects.
+const TeamRoleAdmin = "admin"
+
+// ListOrganizations lists the organizations reachable with every configured credential,
+// organizations visible to more than one token are only returned once. The default token must work, the listing
+// fails otherwise. A failing organization token only leaves out the organizations it alone reaches: the error is
+// logged and the other tokens are still listed, so one revoked token doesn't stop the sync of every organization.
+func (c *Client) ListOrganizations(ctx context.Context) ([]Organization, *v2.RateLimitDescription, error) {
+	slugs := make([]string, 0, len(c.orgCredentials))
+	for slug := range c.orgCredentials {
+		slugs = append(slugs, slug)
+	}
+	sort.Strings(slugs)
+
+	seen := make(map[string]bool)
+	var ret []Organization
+	var ratelimitData *v2.RateLimitDescription
+	listWith := func(cred *credential) error {
+		listOrganizations := func(ctx context.Context, opts PageOptions) (*Page[Organization], error) {
+			page, err := listPage[Organization](ctx, cred, c.url(OrganizationsUrl), "list organizations", opts)
+			if err != nil {
+				return nil, err
+			}
+			ratelimitData = page.RateLimit
+			return page, nil
+		}
+
+		for org, err := range All(ctx, MaxPerPage, listOrganizations) {
+			if err != nil {
+				return err
+			}
+			if seen[org.ID] {
+				continue
+			}
+			seen[org.ID] = true
+			ret = append(ret, org)
+		}
+		return nil
 	}
 
-	if cursor != "" {
-		q := req.URL.Query()
-		q.Set("cursor", cursor)
-		req.URL.RawQuery = q.Encode()
+	if err := listWith(c.credential); err != nil {
+		return nil, nil, err
+	}
+	for _, slug := range slugs {
+		if err := listWith(c.orgCredentials[slug]); err != nil {
+			ctxzap.Extract(ctx).Warn("failed to list organizations with the token of an organization, skipping it",
+				zap.String("org_slug", slug), zap.Error(err))
+		}
 	}
 
-	var target []Organization
-	var ratelimitData v2.RateLimitDescription
-	res, err := c.Do(req,
-		uhttp.WithJSONResponse(&target),
-		uhttp.WithRatelimitData(&ratelimitData),
-	)
+	c.rememberOrgs(ret)
+
+	return ret, ratelimitData, nil
+}
 
+// https://docs.sentry.io/api/organizations/retrieve-an-organization/
+func (c *Client) GetOrganization(ctx context.Context, orgID string) (*Organization, *http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(OrganizationUrl, orgID), nil)
 	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
-		}
-		return nil, nil, nil, fmt.Errorf("failed to list organizations: %w", err)
+		return nil, nil, err
 	}
 
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return nil, nil, nil, fmt.Errorf("failed to list organizations: %s", res.Status)
+	var target Organization
+	res, err := c.do(ctx, orgID, req, "get organization",
+		uhttp.WithJSONResponse(&target),
+	)
+	if err != nil {
+		return nil, res, err
 	}
+	c.rememberOrgRoles(&target)
 
-	return target, res, &ratelimitData, nil
+	return &target, res, nil
 }
 
-// https://docs.sentry.io/api/guides/teams-tutorial/#list-an-organizations-teams-1
-func (c *Client) ListOrganizationMembers(ctx context.Context, orgID, cursor string) ([]OrganizationMember, *http.Response, *v2.RateLimitDescription, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(OrganizationMembersUrl, orgID), nil)
-	if err != nil {
-		return nil, nil, nil, err
+// OrganizationRoles are the roles members can hold in an organization and on its teams.
+type OrganizationRoles struct {
+	OrgRoles  []OrganizationRole
+	TeamRoles []TeamRole
+}
+
+// GetOrganizationRoles returns the role lists of the organization. They are only found in the organization details,
+// which are fetched once: every later GetOrganization refreshes them.
+func (c *Client) GetOrganizationRoles(ctx context.Context, orgID string) (*OrganizationRoles, error) {
+	c.mtx.RLock()
+	roles, ok := c.orgRoles[orgID]
+	c.mtx.RUnlock()
+	if ok {
+		return roles, nil
 	}
 
-	if cursor != "" {
-		q := req.URL.Query()
-		q.Set("cursor", cursor)
-		req.URL.RawQuery = q.Encode()
+	org, _, err := c.GetOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
 	}
 
-	var target []OrganizationMember
-	var ratelimitData v2.RateLimitDescription
-	res, err := c.Do(req,
-		uhttp.WithJSONResponse(&target),
-		uhttp.WithRatelimitData(&ratelimitData),
-	)
+	return &OrganizationRoles{OrgRoles: org.OrgRoleList, TeamRoles: org.TeamRoleList}, nil
+}
 
+// GetAuthProvider returns the SSO configuration of the organization, nil when it has none.
+func (c *Client) GetAuthProvider(ctx context.Context, orgID string) (*AuthProvider, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(OrganizationAuthProviderUrl, orgID), nil)
 	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
+		return nil, err
+	}
+
+	var target *AuthProvider
+	_, err = c.do(ctx, orgID, req, "get auth provider", func(res *uhttp.WrapperResponse) error {
+		// Sentry answers 204 without a body when no auth provider is configured.
+		if res.StatusCode == http.StatusNoContent || len(res.Body) == 0 {
+			return nil
 		}
-		return nil, nil, nil, fmt.Errorf("failed to list organization members: %w", err)
+		target = &AuthProvider{}
+		return json.Unmarshal(res.Body, target)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return nil, nil, nil, fmt.Errorf("failed to list organization members: %s", res.Status)
+	return target, nil
+}
+
+// https://docs.sentry.io/api/guides/teams-tutorial/#list-an-organizations-teams-1
+func (c *Client) ListOrganizationMembers(ctx context.Context, orgID string, opts PageOptions) (*Page[OrganizationMember], error) {
+	return listPage[OrganizationMember](ctx, c.orgCredential(ctx, orgID), c.url(OrganizationMembersUrl, orgID), "list organization members", opts)
+}
+
+// CountOrganizationOwners returns the number of members with the owner role that accepted their invite.
+func (c *Client) CountOrganizationOwners(ctx context.Context, orgID string) (int, error) {
+	q := url.Values{}
+	q.Set("query", "role:owner")
+	ownersURL := c.url(OrganizationMembersUrl, orgID) + "?" + q.Encode()
+
+	listOwners := func(ctx context.Context, opts PageOptions) (*Page[OrganizationMember], error) {
+		return listPage[OrganizationMember](ctx, c.orgCredential(ctx, orgID), ownersURL, "list organization owners", opts)
+	}
+
+	owners := 0
+	for member, err := range All(ctx, MaxPerPage, listOwners) {
+		if err != nil {
+			return 0, err
+		}
+		if IsActiveOwner(member) {
+			owners++
+		}
 	}
 
-	return target, res, &ratelimitData, nil
+	return owners, nil
+}
+
+// IsActiveOwner reports whether a member is an owner of the organization. Invites for the owner role
+// don't count until they are accepted.
+func IsActiveOwner(member OrganizationMember) bool {
+	return member.OrgRole == OrgRoleOwner && !member.Pending
 }
 
 func (c *Client) GetOrganizationMember(ctx context.Context, orgID, memberID string) (*DetailedMember, *http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(OrganizationOneMemberUrl, orgID, memberID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(OrganizationOneMemberUrl, orgID, memberID), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var target DetailedMember
-	res, err := c.Do(req,
+	res, err := c.do(ctx, orgID, req, "get detailed organization member",
 		uhttp.WithJSONResponse(&target),
 	)
-
 	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
-		}
-		return nil, res, fmt.Errorf("failed to get detailed organization member: %w", err)
-	}
-
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return nil, res, fmt.Errorf("failed to get detailed organization member: %s", res.Status)
+		return nil, res, err
 	}
 
 	return &target, res, nil
@@ -117,50 +201,53 @@ func (c *Client) AddMemberToOrganization(ctx context.Context, orgID string, memb
 		return fmt.Errorf("failed to marshal member: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(OrganizationMembersUrl, orgID), bytes.NewReader(v))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(OrganizationMembersUrl, orgID), bytes.NewReader(v))
 	if err != nil {
 		return fmt.Errorf("failed to create request to add member to organization: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	res, err := c.Do(req)
+	_, err = c.do(ctx, orgID, req, "add member to organization")
+	return err
+}
 
+// UpdateMemberOrgRole changes the organization role of a member.
+// https://docs.sentry.io/api/organizations/update-an-organization-members-roles/
+func (c *Client) UpdateMemberOrgRole(ctx context.Context, orgID, memberID, role string) error {
+	v, err := json.Marshal(map[string]string{"orgRole": role})
 	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
-		}
-		return fmt.Errorf("failed to add member to organization: %w", err)
+		return fmt.Errorf("failed to marshal member role: %w", err)
 	}
 
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return fmt.Errorf("failed to add member to organization: %s", res.Status)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.url(OrganizationOneMemberUrl, orgID, memberID), bytes.NewReader(v))
+	if err != nil {
+		return fmt.Errorf("failed to create request to update member role: %w", err)
 	}
 
-	return nil
-}
+	req.Header.Set("Content-Type", "application/json")
 
-func (c *Client) DeleteMemberFromOrganization(ctx context.Context, orgID, userID string) error {
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf(OrganizationOneMemberUrl, orgID, userID), nil)
-	if err != nil {
-		return fmt.Errorf("failed to create request to delete member: %w", err)
-	}
+	_, err = c.do(ctx, orgID, req, "update member role")
+	return err
+}
 
-	res, err := c.Do(req)
-	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
+// RetiredOrgRoles returns the organization roles members may still hold but that can no longer be assigned.
+func RetiredOrgRoles(roles []OrganizationRole) map[string]bool {
+	retired := make(map[string]bool)
+	for _, role := range roles {
+		if role.IsRetired {
+			retired[role.ID] = true
 		}
-		return fmt.Errorf("failed to delete member from organization: %w", err)
 	}
+	return retired
+}
 
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return fmt.Errorf("failed to delete member from organization: %s", res.Status)
+func (c *Client) DeleteMemberFromOrganization(ctx context.Context, orgID, userID string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.url(OrganizationOneMemberUrl, orgID, userID), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request to delete member: %w", err)
 	}
 
-	return nil
+	_, err = c.do(ctx, orgID, req, "delete member from organization")
+	return err
 }
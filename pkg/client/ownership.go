@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// docs: https://docs.sentry.io/product/issues/ownership-rules/
+
+// https://docs.sentry.io/api/projects/retrieve-ownership-configuration-for-a-project/
+func (c *Client) GetProjectOwnership(ctx context.Context, orgID, projectID string) (*ProjectOwnership, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(ProjectOwnershipUrl, orgID, projectID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var target ProjectOwnership
+	if _, err := c.do(ctx, orgID, req, "get project ownership", uhttp.WithJSONResponse(&target)); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// ListProjectCodeOwners returns the CODEOWNERS files imported into a project. The endpoint isn't paginated.
+func (c *Client) ListProjectCodeOwners(ctx context.Context, orgID, projectID string) ([]CodeOwners, error) {
+	q := url.Values{}
+	q.Set("expand", "ownershipSyntax")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(ProjectCodeOwnersUrl, orgID, projectID)+"?"+q.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var target []CodeOwners
+	if _, err := c.do(ctx, orgID, req, "list project codeowners", uhttp.WithJSONResponse(&target)); err != nil {
+		return nil, err
+	}
+
+	return target, nil
+}
+
+// OwnershipRuleOwners returns the owners referenced by rules in Sentry's ownership syntax, in order of first
+// appearance. Users are referenced by email and teams by "#<team slug>".
+func OwnershipRuleOwners(raw string) []string {
+	var owners []string
+	seen := map[string]bool{}
+	for _, line := range strings.Split(raw, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		// The first field is the matcher, e.g. "path:src/billing/*".
+		fields := strings.Fields(line)
+		for _, owner := range fields[1:] {
+			if !seen[owner] {
+				seen[owner] = true
+				owners = append(owners, owner)
+			}
+		}
+	}
+	return owners
+}
+
+// OwnerTeamSlug returns the team slug of a "#<team slug>" owner.
+func OwnerTeamSlug(owner string) (string, bool) {
+	return strings.CutPrefix(owner, "#")
+}
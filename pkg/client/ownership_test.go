@@ -0,0 +1,30 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOwnershipRuleOwners(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  string
+		want []string
+	}{
+		{name: "empty", raw: "", want: nil},
+		{name: "comments and blank lines", raw: "# ops owns it all\n\n  \n", want: nil},
+		{
+			name: "users and teams",
+			raw:  "path:src/* #backend alice@acme.test\nurl:*/checkout/* alice@acme.test #frontend\n",
+			want: []string{"#backend", "alice@acme.test", "#frontend"},
+		},
+		{name: "rule without owners", raw: "tags.level:fatal", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, OwnershipRuleOwners(tt.raw))
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/http"
+	"strconv"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// MaxPerPage is the largest page size Sentry accepts on its list endpoints.
+const MaxPerPage = 100
+
+// PageOptions selects the page returned by a cursor-paginated list call.
+type PageOptions struct {
+	Cursor string
+	// PerPage is the number of items per page, zero keeps the endpoint default.
+	PerPage int
+}
+
+// Page is a single page of results from a cursor-paginated list endpoint.
+// NextCursor is empty on the last page.
+type Page[T any] struct {
+	Items      []T
+	NextCursor string
+	RateLimit  *v2.RateLimitDescription
+}
+
+// https://docs.sentry.io/api/pagination/
+func listPage[T any](ctx context.Context, cred *credential, url, action string, opts PageOptions) (*Page[T], error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	q := req.URL.Query()
+	if opts.Cursor != "" {
+		q.Set("cursor", opts.Cursor)
+	}
+	if opts.PerPage > 0 {
+		q.Set("per_page", strconv.Itoa(min(opts.PerPage, MaxPerPage)))
+	}
+	req.URL.RawQuery = q.Encode()
+
+	var target []T
+	var ratelimitData v2.RateLimitDescription
+	res, err := send(ctx, cred, req, action,
+		uhttp.WithJSONResponse(&target),
+		uhttp.WithRatelimitData(&ratelimitData),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &Page[T]{
+		Items:     target,
+		RateLimit: &ratelimitData,
+	}
+	if HasNextPage(res) {
+		page.NextCursor = NextCursor(res)
+	}
+
+	return page, nil
+}
+
+// All walks every page of a list call, yielding each item in turn.
+// Iteration stops after the last page or after yielding the first error.
+func All[T any](ctx context.Context, perPage int, list func(ctx context.Context, opts PageOptions) (*Page[T], error)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		opts := PageOptions{PerPage: perPage}
+		for {
+			page, err := list(ctx, opts)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+
+			for _, item := range page.Items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if page.NextCursor == "" {
+				return
+			}
+			opts.Cursor = page.NextCursor
+		}
+	}
+}
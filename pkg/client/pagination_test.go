@@ -0,0 +1,72 @@
+package client_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/conductorone/baton-sentry/pkg/client"
+	"github.com/conductorone/baton-sentry/pkg/sentrytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAll(t *testing.T) {
+	tests := []struct {
+		name     string
+		teams    int
+		pageSize int
+		perPage  int
+		// take stops the iteration after that many teams, zero reads them all.
+		take         int
+		wantTeams    int
+		wantRequests int
+	}{
+		{name: "follows the link cursor", teams: 5, pageSize: 2, wantTeams: 5, wantRequests: 3},
+		// The last page still links to a next cursor, with results="false".
+		{name: "stops without results", teams: 4, pageSize: 2, wantTeams: 4, wantRequests: 2},
+		{name: "empty list", teams: 0, pageSize: 2, wantTeams: 0, wantRequests: 1},
+		// The server would serve 500 teams per page, the client never asks for more than MaxPerPage.
+		{name: "caps per page", teams: 150, pageSize: 500, perPage: 1000, wantTeams: 150, wantRequests: 2},
+		{name: "stops when the consumer breaks", teams: 5, pageSize: 2, take: 3, wantTeams: 3, wantRequests: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+			server := sentrytest.NewServer(t)
+			server.PageSize = tt.pageSize
+			org := server.AddOrganization("acme", "Acme")
+			for i := range tt.teams {
+				server.AddTeam("acme", fmt.Sprintf("team-%d", i), fmt.Sprintf("Team %d", i))
+			}
+			c, err := client.New(ctx, sentrytest.Token, client.WithBaseURL(server.URL))
+			require.NoError(t, err)
+
+			listTeams := func(ctx context.Context, opts client.PageOptions) (*client.Page[client.Team], error) {
+				return c.ListTeams(ctx, org.ID, opts)
+			}
+			var slugs []string
+			for team, err := range client.All(ctx, tt.perPage, listTeams) {
+				require.NoError(t, err)
+				slugs = append(slugs, team.Slug)
+				if len(slugs) == tt.take {
+					break
+				}
+			}
+
+			assert.Len(t, slugs, tt.wantTeams)
+			for i, slug := range slugs {
+				assert.Equal(t, fmt.Sprintf("team-%d", i), slug)
+			}
+			requests := 0
+			for _, req := range server.Requests() {
+				if req == fmt.Sprintf("GET /api/0/organizations/%s/teams/", org.ID) {
+					requests++
+				}
+			}
+			assert.Equal(t, tt.wantRequests, requests)
+		})
+	}
+}
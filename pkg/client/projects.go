@@ -2,184 +2,62 @@ package client
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 
-	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/uhttp"
 )
 
-func (c *Client) ListProjects(ctx context.Context, orgID, cursor string) ([]Project, *http.Response, *v2.RateLimitDescription, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(OrganizationProjectsUrl, orgID), nil)
+// ListProjects lists a page of the organization's projects and remembers their organization for FindProjectOrgID.
+func (c *Client) ListProjects(ctx context.Context, orgID string, opts PageOptions) (*Page[Project], error) {
+	page, err := listPage[Project](ctx, c.orgCredential(ctx, orgID), c.url(OrganizationProjectsUrl, orgID), "list projects", opts)
 	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	if cursor != "" {
-		q := req.URL.Query()
-		q.Set("cursor", cursor)
-		req.URL.RawQuery = q.Encode()
-	}
-
-	var target []Project
-	var ratelimitData v2.RateLimitDescription
-	res, err := c.Do(req,
-		uhttp.WithJSONResponse(&target),
-		uhttp.WithRatelimitData(&ratelimitData),
-	)
-
-	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
-		}
-		return nil, nil, nil, fmt.Errorf("failed to list projects: %w", err)
-	}
-
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return nil, nil, nil, fmt.Errorf("failed to list projects: %s", res.Status)
+		return nil, err
 	}
+	c.rememberProjects(orgID, page.Items)
 
-	return target, res, &ratelimitData, nil
+	return page, nil
 }
 
-func (c *Client) ListTeamProjects(ctx context.Context, orgID, teamID, cursor string) ([]Project, *http.Response, *v2.RateLimitDescription, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(TeamProjectsUrl, orgID, teamID), nil)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	if cursor != "" {
-		q := req.URL.Query()
-		q.Set("cursor", cursor)
-		req.URL.RawQuery = q.Encode()
-	}
-
-	var target []Project
-	var ratelimitData v2.RateLimitDescription
-	res, err := c.Do(req,
-		uhttp.WithJSONResponse(&target),
-		uhttp.WithRatelimitData(&ratelimitData),
-	)
-
-	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
-		}
-		return nil, nil, nil, fmt.Errorf("failed to list projects: %w", err)
-	}
-
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return nil, nil, nil, fmt.Errorf("failed to list projects: %s", res.Status)
-	}
-
-	return target, res, &ratelimitData, nil
+func (c *Client) ListTeamProjects(ctx context.Context, orgID, teamID string, opts PageOptions) (*Page[Project], error) {
+	return listPage[Project](ctx, c.orgCredential(ctx, orgID), c.url(TeamProjectsUrl, orgID, teamID), "list team projects", opts)
 }
 
 // https://docs.sentry.io/api/projects/list-a-projects-organization-members/
 // Returns a list of active organization members that belong to any team assigned to the project.
-func (c *Client) ListProjectMembers(ctx context.Context, orgID, projectID, cursor string) ([]ProjectMember, *http.Response, *v2.RateLimitDescription, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(ProjectMembersUrl, orgID, projectID), nil)
-	if err != nil {
-		return nil, nil, nil, err
-	}
-
-	var target []ProjectMember
-	var ratelimitData v2.RateLimitDescription
-	res, err := c.Do(req,
-		uhttp.WithJSONResponse(&target),
-		uhttp.WithRatelimitData(&ratelimitData),
-	)
-
-	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
-		}
-		return nil, nil, nil, fmt.Errorf("failed to list project members: %w", err)
-	}
-
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return nil, nil, nil, fmt.Errorf("failed to list project members: %s", res.Status)
-	}
-
-	return target, res, &ratelimitData, nil
+func (c *Client) ListProjectMembers(ctx context.Context, orgID, projectID string, opts PageOptions) (*Page[ProjectMember], error) {
+	return listPage[ProjectMember](ctx, c.orgCredential(ctx, orgID), c.url(ProjectMembersUrl, orgID, projectID), "list project members", opts)
 }
 
 func (c *Client) AddTeamToProject(ctx context.Context, orgID, projectID, teamID string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf(ProvisionProjectTeamUrl, orgID, projectID, teamID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(ProvisionProjectTeamUrl, orgID, projectID, teamID), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := c.Do(req)
-
-	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
-		}
-		return nil, fmt.Errorf("failed to add team to project: %w", err)
-	}
-
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return nil, fmt.Errorf("failed to add team to project: %s", res.Status)
-	}
-
-	return res, nil
+	return c.do(ctx, orgID, req, "add team to project")
 }
 
 func (c *Client) DeleteTeamFromProject(ctx context.Context, orgID, projectID, teamID string) (*http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, fmt.Sprintf(ProvisionProjectTeamUrl, orgID, projectID, teamID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, c.url(ProvisionProjectTeamUrl, orgID, projectID, teamID), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := c.Do(req)
-
-	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
-		}
-		return nil, fmt.Errorf("failed to delete team from project: %w", err)
-	}
-
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return nil, fmt.Errorf("failed to delete team from project: %s", res.Status)
-	}
-
-	return res, nil
+	return c.do(ctx, orgID, req, "delete team from project")
 }
 
 func (c *Client) GetProject(ctx context.Context, orgID, projectID string) (*DetailedProject, *http.Response, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(ProjectsUrl, orgID, projectID), nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(ProjectsUrl, orgID, projectID), nil)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	var target DetailedProject
-	res, err := c.Do(req,
+	res, err := c.do(ctx, orgID, req, "get project",
 		uhttp.WithJSONResponse(&target),
 	)
-
 	if err != nil {
-		if res != nil {
-			logBody(ctx, res.Body)
-		}
-		return nil, nil, fmt.Errorf("failed to get project: %w", err)
-	}
-
-	defer res.Body.Close()
-	if res.StatusCode < 200 || res.StatusCode >= 300 {
-		logBody(ctx, res.Body)
-		return nil, nil, fmt.Errorf("failed to get project: %s", res.Status)
+		return nil, nil, err
 	}
 
 	return &target, res, nil
@@ -0,0 +1,111 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// https://docs.sentry.io/api/ratelimits/
+const (
+	rateLimitLimitHeader     = "X-Sentry-Rate-Limit-Limit"
+	rateLimitRemainingHeader = "X-Sentry-Rate-Limit-Remaining"
+	rateLimitResetHeader     = "X-Sentry-Rate-Limit-Reset"
+	retryAfterHeader         = "Retry-After"
+
+	// maxRateLimitRetries is how many times a rate limited request is retried before giving up.
+	maxRateLimitRetries = 5
+	// maxRateLimitWait caps a single wait, longer waits are left to the SDK's own retry handling.
+	maxRateLimitWait = time.Minute
+	// lowRemainingRatio is the fraction of the budget below which requests are held until the window resets.
+	lowRemainingRatio = 0.1
+)
+
+// rateLimiter tracks the rate limit budget Sentry reports for one API token.
+// Sentry applies limits per token and endpoint, so this is a conservative view of the most recent response.
+type rateLimiter struct {
+	mtx       sync.Mutex
+	limit     int
+	remaining int
+	resetAt   time.Time
+
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		remaining: -1,
+		now:       time.Now,
+		sleep:     sleepContext,
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// wait blocks while the remaining budget is low, until the rate limit window resets.
+func (r *rateLimiter) wait(ctx context.Context) error {
+	r.mtx.Lock()
+	var delay time.Duration
+	if r.remaining >= 0 && float64(r.remaining) <= float64(r.limit)*lowRemainingRatio {
+		delay = r.resetAt.Sub(r.now())
+	}
+	r.mtx.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	ctxzap.Extract(ctx).Debug("sentry rate limit budget is low, waiting for reset", zap.Duration("delay", delay))
+	return r.sleep(ctx, min(delay, maxRateLimitWait))
+}
+
+// update records the rate limit headers of a response.
+func (r *rateLimiter) update(res *http.Response) {
+	limit, err := strconv.Atoi(res.Header.Get(rateLimitLimitHeader))
+	if err != nil {
+		return
+	}
+	remaining, err := strconv.Atoi(res.Header.Get(rateLimitRemainingHeader))
+	if err != nil {
+		return
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.limit = limit
+	r.remaining = remaining
+	if reset, err := strconv.ParseInt(res.Header.Get(rateLimitResetHeader), 10, 64); err == nil {
+		r.resetAt = time.Unix(reset, 0)
+	}
+}
+
+// retryDelay returns how long to wait before retrying a rate limited response, preferring Retry-After
+// over the reset time of the window.
+func (r *rateLimiter) retryDelay(res *http.Response) time.Duration {
+	if seconds, err := strconv.ParseFloat(res.Header.Get(retryAfterHeader), 64); err == nil && seconds >= 0 {
+		return time.Duration(seconds * float64(time.Second))
+	}
+
+	if reset, err := strconv.ParseInt(res.Header.Get(rateLimitResetHeader), 10, 64); err == nil {
+		if delay := time.Unix(reset, 0).Sub(r.now()); delay > 0 {
+			return delay
+		}
+	}
+
+	return time.Second
+}
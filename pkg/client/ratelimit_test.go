@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSendRetriesRateLimitedRequests(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		if calls < 3 {
+			w.Header().Set(retryAfterHeader, "2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"detail": "You are attempting to use this endpoint too frequently."}`))
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	defer server.Close()
+
+	cred, err := newCredential(ctx, "token")
+	require.NoError(t, err)
+	var slept []time.Duration
+	cred.limiter.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL, http.NoBody)
+	require.NoError(t, err)
+
+	_, err = send(ctx, cred, req, "list teams")
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, []time.Duration{2 * time.Second, 2 * time.Second}, slept)
+}
+
+func TestSendGivesUpAfterMaxRetries(t *testing.T) {
+	ctx := context.Background()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set(retryAfterHeader, "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	cred, err := newCredential(ctx, "token")
+	require.NoError(t, err)
+	cred.limiter.sleep = func(context.Context, time.Duration) error { return nil }
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, server.URL, nil)
+	require.NoError(t, err)
+
+	_, err = send(ctx, cred, req, "delete member")
+	require.Error(t, err)
+	assert.Equal(t, maxRateLimitRetries+1, calls)
+}
+
+func TestRateLimiterWaitsWhenBudgetIsLow(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+
+	limiter := newRateLimiter()
+	limiter.now = func() time.Time { return now }
+	var slept time.Duration
+	limiter.sleep = func(_ context.Context, d time.Duration) error {
+		slept = d
+		return nil
+	}
+
+	res := &http.Response{Header: http.Header{}}
+	res.Header.Set(rateLimitLimitHeader, "40")
+	res.Header.Set(rateLimitRemainingHeader, "20")
+	res.Header.Set(rateLimitResetHeader, strconv.FormatInt(now.Add(3*time.Second).Unix(), 10))
+	limiter.update(res)
+
+	require.NoError(t, limiter.wait(ctx))
+	assert.Zero(t, slept)
+
+	res.Header.Set(rateLimitRemainingHeader, "2")
+	limiter.update(res)
+
+	require.NoError(t, limiter.wait(ctx))
+	assert.Equal(t, 3*time.Second, slept)
+}
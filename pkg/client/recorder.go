@@ -0,0 +1,265 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RecordMode selects whether a recorder captures live Sentry responses or serves captured ones.
+type RecordMode int
+
+const (
+	// RecordModeReplay serves every response from the recording, requests never leave the process.
+	RecordModeReplay RecordMode = iota
+	// RecordModeRecord sends requests to Sentry and saves the scrubbed responses to the recording.
+	RecordModeRecord
+)
+
+// WithRecorder records every response to the JSON file at path, or replays responses from it.
+// Recorded responses have API tokens, emails and DSNs scrubbed before they are written.
+func WithRecorder(path string, mode RecordMode) Option {
+	return func(c *Client) {
+		c.recorder = &recorder{
+			path: path,
+			mode: mode,
+		}
+	}
+}
+
+// recordedHeaders are the response headers kept in a recording, everything else is dropped.
+var recordedHeaders = []string{
+	"Content-Type",
+	"Link",
+	rateLimitLimitHeader,
+	rateLimitRemainingHeader,
+	rateLimitResetHeader,
+	retryAfterHeader,
+}
+
+type recording struct {
+	Interactions []interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string `json:"method"`
+	// URL is the path and query of the request, without the host, so a recording replays against any base URL.
+	URL string `json:"url"`
+}
+
+type recordedResponse struct {
+	StatusCode int               `json:"status_code"`
+	Header     map[string]string `json:"header,omitempty"`
+	// Body holds JSON responses as is, RawBody holds anything else such as avatar images.
+	Body    json.RawMessage `json:"body,omitempty"`
+	RawBody []byte          `json:"raw_body,omitempty"`
+}
+
+type recorder struct {
+	path string
+	mode RecordMode
+
+	mtx       sync.Mutex
+	recording recording
+	// replayed marks the interactions already served, so repeated requests are answered in recorded order.
+	replayed []bool
+	scrubber *scrubber
+}
+
+func (r *recorder) load() error {
+	// Replayed requests are scrubbed too, they are matched against the scrubbed URLs of the recording.
+	r.scrubber = newScrubber()
+	if r.mode == RecordModeRecord {
+		return nil
+	}
+
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return fmt.Errorf("failed to read recording: %w", err)
+	}
+	if err := json.Unmarshal(data, &r.recording); err != nil {
+		return fmt.Errorf("failed to parse recording %s: %w", r.path, err)
+	}
+	r.replayed = make([]bool, len(r.recording.Interactions))
+
+	return nil
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (r *recorder) wrap(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+
+	return roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		if r.mode == RecordModeReplay {
+			return r.replay(req)
+		}
+		return r.record(next, req)
+	})
+}
+
+func (r *recorder) record(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	res, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	recorded := recordedResponse{
+		StatusCode: res.StatusCode,
+		Header:     map[string]string{},
+	}
+	for _, name := range recordedHeaders {
+		if v := res.Header.Get(name); v != "" {
+			recorded.Header[name] = r.scrubber.scrub(v)
+		}
+	}
+	switch {
+	case len(body) == 0:
+	case json.Valid(body):
+		recorded.Body = json.RawMessage(r.scrubber.scrub(string(body)))
+	default:
+		recorded.RawBody = body
+	}
+
+	r.recording.Interactions = append(r.recording.Interactions, interaction{
+		Request: recordedRequest{
+			Method: req.Method,
+			URL:    r.scrubber.scrub(req.URL.RequestURI()),
+		},
+		Response: recorded,
+	})
+
+	if err := r.save(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// save rewrites the whole recording after every interaction, so an interrupted run still leaves a usable file.
+func (r *recorder) save() error {
+	data, err := json.MarshalIndent(r.recording, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal recording: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create recording directory: %w", err)
+	}
+
+	return os.WriteFile(r.path, append(data, '\n'), 0o600)
+}
+
+// replay answers with the first recorded response for the same method and URL that was not served yet,
+// once all of them were served the last one keeps being returned.
+func (r *recorder) replay(req *http.Request) (*http.Response, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	url := r.scrubber.scrub(req.URL.RequestURI())
+	match := -1
+	for i, in := range r.recording.Interactions {
+		if in.Request.Method != req.Method || in.Request.URL != url {
+			continue
+		}
+		match = i
+		if !r.replayed[i] {
+			break
+		}
+	}
+	if match < 0 {
+		return nil, fmt.Errorf("no recorded response for %s %s in %s", req.Method, url, r.path)
+	}
+	r.replayed[match] = true
+
+	recorded := r.recording.Interactions[match].Response
+	header := http.Header{}
+	for name, v := range recorded.Header {
+		header.Set(name, v)
+	}
+	body := []byte(recorded.Body)
+	if recorded.RawBody != nil {
+		body = recorded.RawBody
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.StatusCode, http.StatusText(recorded.StatusCode)),
+		StatusCode:    recorded.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+var (
+	// DSNs look like https://<public key>[:<secret key>]@o<org>.ingest.sentry.io/<project>.
+	dsnPattern = regexp.MustCompile(`(https?://)[0-9a-f]{32}(?::[0-9a-f]{32})?@[A-Za-z0-9.-]+(?::\d+)?`)
+	// Org and user auth tokens have a sntrys_/sntryu_ prefix, older tokens are 64 hex characters.
+	tokenPattern       = regexp.MustCompile(`\bsntry[a-z]?_[A-Za-z0-9+/=_.-]+|\b[0-9a-f]{64}\b`)
+	emailPattern       = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	placeholderPattern = regexp.MustCompile(`^user\d+@example\.com$`)
+	secretPattern      = regexp.MustCompile(`"(securityToken|public|secret|token|clientSecret)"(\s*):(\s*)"[^"]*"`)
+)
+
+// scrubber removes credentials and personal data from recorded payloads. Emails are replaced consistently,
+// the same address always becomes the same placeholder, so recordings keep their relationships.
+type scrubber struct {
+	emails map[string]string
+}
+
+func newScrubber() *scrubber {
+	return &scrubber{
+		emails: map[string]string{},
+	}
+}
+
+func (s *scrubber) scrub(v string) string {
+	v = dsnPattern.ReplaceAllString(v, "${1}"+strings.Repeat("0", 32)+"@o0.ingest.example.com")
+	v = tokenPattern.ReplaceAllString(v, "REDACTED")
+	v = secretPattern.ReplaceAllString(v, `"$1"$2:$3"REDACTED"`)
+	v = emailPattern.ReplaceAllStringFunc(v, func(email string) string {
+		email = strings.ToLower(email)
+		// Placeholders come back in replayed responses and from there into request URLs, they are kept as is.
+		if placeholderPattern.MatchString(email) {
+			return email
+		}
+		if _, ok := s.emails[email]; !ok {
+			s.emails[email] = fmt.Sprintf("user%d@example.com", len(s.emails)+1)
+		}
+		return s.emails[email]
+	})
+
+	return v
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ListTrustedRelays returns the Relays trusted by the organization. They are an organization setting, only
+// returned with the organization details.
+func (c *Client) ListTrustedRelays(ctx context.Context, orgID string) ([]TrustedRelay, error) {
+	org, _, err := c.GetOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	return org.TrustedRelays, nil
+}
+
+// SetTrustedRelays replaces the Relays trusted by the organization. Relays keep their creation date as long as
+// their public key stays in the list.
+func (c *Client) SetTrustedRelays(ctx context.Context, orgID string, relays []TrustedRelay) error {
+	type trustedRelay struct {
+		Name        string `json:"name"`
+		PublicKey   string `json:"publicKey"`
+		Description string `json:"description"`
+	}
+
+	update := struct {
+		TrustedRelays []trustedRelay `json:"trustedRelays"`
+	}{
+		TrustedRelays: make([]trustedRelay, 0, len(relays)),
+	}
+	for _, relay := range relays {
+		update.TrustedRelays = append(update.TrustedRelays, trustedRelay{
+			Name:        relay.Name,
+			PublicKey:   relay.PublicKey,
+			Description: relay.Description,
+		})
+	}
+
+	body, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to marshal trusted relays: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.url(OrganizationUrl, orgID), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	_, err = c.do(ctx, orgID, req, "update trusted relays")
+	return err
+}
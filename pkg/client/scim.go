@@ -0,0 +1,115 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// docs: https://docs.sentry.io/api/scim/
+
+const (
+	SCIMUserSchema  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMPatchSchema = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+)
+
+// WithSCIMTokens provisions the members of organizations set up with SAML and SCIM through their SCIM endpoints,
+// keyed by organization slug. Sentry's member API refuses most changes to members and teams the identity
+// provider manages, while the SCIM endpoints accept them.
+func WithSCIMTokens(tokens map[string]string) Option {
+	return func(c *Client) {
+		c.scimTokens = tokens
+	}
+}
+
+// HasSCIM reports whether the organization's members are provisioned through SCIM.
+func (c *Client) HasSCIM(ctx context.Context, orgID string) bool {
+	_, ok := c.lookupCredential(ctx, c.scimCredentials, orgID)
+	return ok
+}
+
+// doSCIM sends a request to the SCIM endpoints of an organization with its SCIM token.
+func (c *Client) doSCIM(ctx context.Context, orgID string, req *http.Request, action string, options ...uhttp.DoOption) (*http.Response, error) {
+	cred, ok := c.lookupCredential(ctx, c.scimCredentials, orgID)
+	if !ok {
+		return nil, status.Errorf(codes.FailedPrecondition, "failed to %s: no SCIM token for organization %s", action, orgID)
+	}
+
+	return c.doWith(ctx, cred, req, action, options...)
+}
+
+// CreateSCIMUser adds a member to the organization. Unlike an invite the member is active right away, the
+// identity provider already vouched for them.
+// https://docs.sentry.io/api/scim/provision-a-new-organization-member/
+func (c *Client) CreateSCIMUser(ctx context.Context, orgID string, user SCIMUser) (*SCIMUser, error) {
+	user.Schemas = []string{SCIMUserSchema}
+	body, err := json.Marshal(user)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal SCIM user: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url(OrganizationSCIMUsersUrl, orgID), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/scim+json")
+
+	var target SCIMUser
+	if _, err := c.doSCIM(ctx, orgID, req, "create SCIM user", uhttp.WithJSONResponse(&target)); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// DeactivateSCIMUser sets active=false on a member, which removes them from the organization.
+// https://docs.sentry.io/api/scim/update-an-organization-members-attributes/
+func (c *Client) DeactivateSCIMUser(ctx context.Context, orgID, memberID string) error {
+	return c.patchSCIM(ctx, orgID, c.url(OrganizationSCIMUserUrl, orgID, memberID), "deactivate SCIM user", SCIMOperation{
+		Op:    "replace",
+		Value: map[string]bool{"active": false},
+	})
+}
+
+// AddSCIMGroupMember adds a member to a team through its SCIM group.
+// https://docs.sentry.io/api/scim/update-a-teams-attributes/
+func (c *Client) AddSCIMGroupMember(ctx context.Context, orgID, teamID, memberID string) error {
+	return c.patchSCIM(ctx, orgID, c.url(OrganizationSCIMGroupUrl, orgID, teamID), "add SCIM group member", SCIMOperation{
+		Op:    "add",
+		Path:  "members",
+		Value: []map[string]string{{"value": memberID}},
+	})
+}
+
+// RemoveSCIMGroupMember removes a member from a team through its SCIM group.
+func (c *Client) RemoveSCIMGroupMember(ctx context.Context, orgID, teamID, memberID string) error {
+	return c.patchSCIM(ctx, orgID, c.url(OrganizationSCIMGroupUrl, orgID, teamID), "remove SCIM group member", SCIMOperation{
+		Op:   "remove",
+		Path: fmt.Sprintf("members[value eq \"%s\"]", memberID),
+	})
+}
+
+func (c *Client) patchSCIM(ctx context.Context, orgID, url, action string, operations ...SCIMOperation) error {
+	body, err := json.Marshal(SCIMPatch{
+		Schemas:    []string{SCIMPatchSchema},
+		Operations: operations,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal SCIM patch: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPatch, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/scim+json")
+
+	_, err = c.doSCIM(ctx, orgID, req, action)
+	return err
+}
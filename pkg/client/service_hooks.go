@@ -0,0 +1,64 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/conductorone/baton-sdk/pkg/uhttp"
+)
+
+// https://docs.sentry.io/api/projects/list-a-projects-service-hooks/
+func (c *Client) ListServiceHooks(ctx context.Context, orgID, projectID string, opts PageOptions) (*Page[ServiceHook], error) {
+	return listPage[ServiceHook](ctx, c.orgCredential(ctx, orgID), c.url(ProjectServiceHooksUrl, orgID, projectID), "list service hooks", opts)
+}
+
+// https://docs.sentry.io/api/projects/retrieve-a-service-hook/
+func (c *Client) GetServiceHook(ctx context.Context, orgID, projectID, hookID string) (*ServiceHook, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(ProjectServiceHookUrl, orgID, projectID, hookID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var target ServiceHook
+	if _, err := c.do(ctx, orgID, req, "get service hook", uhttp.WithJSONResponse(&target)); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// GetPlugin returns a plugin of the project with its configuration. The plugins of a project, without their
+// configuration, are listed in the project details.
+func (c *Client) GetPlugin(ctx context.Context, orgID, projectID, pluginID string) (*Plugin, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url(ProjectPluginUrl, orgID, projectID, pluginID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var target Plugin
+	if _, err := c.do(ctx, orgID, req, "get plugin", uhttp.WithJSONResponse(&target)); err != nil {
+		return nil, err
+	}
+
+	return &target, nil
+}
+
+// PluginTargetURLs returns the URLs a plugin sends data to, read from its URL settings. The webhooks plugin
+// keeps one URL per line.
+func PluginTargetURLs(plugin Plugin) []string {
+	var urls []string
+	for _, field := range plugin.Config {
+		value, ok := field.Value.(string)
+		if !ok || (field.Type != "url" && !strings.Contains(strings.ToLower(field.Name), "url")) {
+			continue
+		}
+		for _, line := range strings.Split(value, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				urls = append(urls, line)
+			}
+		}
+	}
+
+	return urls
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPluginTargetURLs(t *testing.T) {
+	tests := []struct {
+		name   string
+		config []PluginConfigField
+		want   []string
+	}{
+		{name: "no configuration", config: nil, want: nil},
+		{
+			name: "webhooks keeps one URL per line",
+			config: []PluginConfigField{
+				{Name: "urls", Type: "textarea", Value: "https://hooks.acme.test/sentry\n\n  https://backup.acme.test/sentry  \n"},
+			},
+			want: []string{"https://hooks.acme.test/sentry", "https://backup.acme.test/sentry"},
+		},
+		{
+			name: "url field",
+			config: []PluginConfigField{
+				{Name: "endpoint", Type: "url", Value: "https://splunk.acme.test:8088"},
+				{Name: "token", Type: "secret", Value: "********"},
+			},
+			want: []string{"https://splunk.acme.test:8088"},
+		},
+		{
+			name:   "unset value",
+			config: []PluginConfigField{{Name: "server_url", Type: "text", Value: nil}},
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, PluginTargetURLs(Plugin{Config: tt.config}))
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/conductorone/baton-sentry/pkg/client"
+	"github.com/conductorone/baton-sentry/pkg/sentrytest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTokenServer serves acme with the default token, globex and initech only with their own tokens. The globex
+// token reaches acme too.
+func newTokenServer(t *testing.T) (*sentrytest.Server, map[string]client.Organization) {
+	t.Helper()
+	t.Setenv("BATON_DISABLE_HTTP_CACHE", "true")
+
+	server := sentrytest.NewServer(t)
+	server.PageSize = 1
+	orgs := map[string]client.Organization{
+		"acme":    server.AddOrganization("acme", "Acme"),
+		"globex":  server.AddOrganization("globex", "Globex"),
+		"initech": server.AddOrganization("initech", "Initech"),
+	}
+	server.RestrictOrganization("globex")
+	server.RestrictOrganization("initech")
+	server.AddToken("globex-token", "globex", "acme")
+	server.AddToken("initech-token", "initech")
+
+	return server, orgs
+}
+
+func orgSlugs(orgs []client.Organization) []string {
+	ret := make([]string, 0, len(orgs))
+	for _, org := range orgs {
+		ret = append(ret, org.Slug)
+	}
+	return ret
+}
+
+func TestListOrganizationsMergesTokens(t *testing.T) {
+	ctx := context.Background()
+	server, _ := newTokenServer(t)
+	c, err := client.New(ctx, sentrytest.Token, client.WithBaseURL(server.URL), client.WithOrgTokens(map[string]string{
+		"globex":  "globex-token",
+		"initech": "initech-token",
+	}))
+	require.NoError(t, err)
+
+	orgs, _, err := c.ListOrganizations(ctx)
+	require.NoError(t, err)
+	// acme is reached by the default and the globex tokens, it is only listed once.
+	assert.Equal(t, []string{"acme", "globex", "initech"}, orgSlugs(orgs))
+}
+
+func TestOrgTokenRouting(t *testing.T) {
+	ctx := context.Background()
+	server, orgs := newTokenServer(t)
+	c, err := client.New(ctx, sentrytest.Token, client.WithBaseURL(server.URL), client.WithOrgTokens(map[string]string{
+		"globex":  "globex-token",
+		"initech": "initech-token",
+	}))
+	require.NoError(t, err)
+
+	// Each organization is only reachable with its own token, by slug or by ID once the slug is resolved.
+	for _, org := range orgs {
+		for _, id := range []string{org.Slug, org.ID} {
+			got, _, err := c.GetOrganization(ctx, id)
+			require.NoError(t, err, id)
+			assert.Equal(t, org.ID, got.ID)
+		}
+	}
+}
+
+func TestListOrganizationsWithFailingToken(t *testing.T) {
+	ctx := context.Background()
+	server, _ := newTokenServer(t)
+
+	c, err := client.New(ctx, sentrytest.Token, client.WithBaseURL(server.URL), client.WithOrgTokens(map[string]string{
+		"globex":  "revoked-token",
+		"initech": "initech-token",
+	}))
+	require.NoError(t, err)
+	orgs, _, err := c.ListOrganizations(ctx)
+	require.NoError(t, err)
+	// Only the organizations of the failing token are left out.
+	assert.Equal(t, []string{"acme", "initech"}, orgSlugs(orgs))
+
+	// The default token has to work.
+	c, err = client.New(ctx, "revoked-token", client.WithBaseURL(server.URL), client.WithOrgTokens(map[string]string{
+		"initech": "initech-token",
+	}))
+	require.NoError(t, err)
+	_, _, err = c.ListOrganizations(ctx)
+	assert.Error(t, err)
+}
@@ -1,16 +1,27 @@
 package client
 
+// BaseUrl is the default Sentry host, every other URL is relative to it.
+const BaseUrl = "https://sentry.io/"
+
 const (
-	BaseUrl                  = "https://sentry.io/api/0/"
-	OrganizationsUrl         = BaseUrl + "organizations/"
+	// Uploaded avatars are served outside of the API.
+	//	{organization,team,project}-avatar/{avatar_uuid}/ or avatar/{avatar_uuid}/ for users
+	AvatarUrl = "%s/%s/"
+
+	ApiUrl                   = "api/0/"
+	OrganizationsUrl         = ApiUrl + "organizations/"
+	OrganizationUrl          = OrganizationsUrl + "%s/"
 	OrganizationMembersUrl   = OrganizationsUrl + "%s/members/"
 	OrganizationOneMemberUrl = OrganizationsUrl + "%s/members/%s/"
 	OrganizationTeamsUrl     = OrganizationsUrl + "%s/teams/"
 	OrganizationProjectsUrl  = OrganizationsUrl + "%s/projects/"
 
+	//	teams/{organization_id_or_slug}/{team_id_or_slug}/
+	TeamUrl = ApiUrl + "teams/%s/%s/"
+
 	//https://docs.sentry.io/api/teams/list-a-teams-members/
 	//	teams/{organization_id_or_slug}/{team_id_or_slug}/members/
-	TeamMembersUrl = BaseUrl + "teams/%s/%s/members/"
+	TeamMembersUrl = ApiUrl + "teams/%s/%s/members/"
 
 	//- grant team member https://docs.sentry.io/api/teams/add-an-organization-member-to-a-team/
 	//- revoke team member https://docs.sentry.io/api/teams/delete-an-organization-member-from-a-team/
@@ -19,7 +30,7 @@ const (
 	ProvisionTeamMemberUrl = OrganizationMembersUrl + "%s/teams/%s/"
 
 	//	projects/{organization_id_or_slug}/{project_id_or_slug}/
-	ProjectsUrl = BaseUrl + "projects/%s/%s/"
+	ProjectsUrl = ApiUrl + "projects/%s/%s/"
 
 	//	projects/{organization_id_or_slug}/{project_id_or_slug}/
 	ProjectMembersUrl = ProjectsUrl + "members/"
@@ -29,5 +40,74 @@ const (
 	ProvisionProjectTeamUrl = ProjectsUrl + "teams/%s/"
 
 	// teams/{organization_id_or_slug}/{team_id_or_slug}/projects/.
-	TeamProjectsUrl = BaseUrl + "teams/%s/%s/projects/"
+	TeamProjectsUrl = ApiUrl + "teams/%s/%s/projects/"
+
+	// https://docs.sentry.io/api/events/bulk-mutate-an-organizations-issues/
+	//	organizations/{organization_id_or_slug}/issues/
+	OrganizationIssuesUrl = OrganizationsUrl + "%s/issues/"
+
+	// metric alert rules
+	//	organizations/{organization_id_or_slug}/alert-rules/{alert_rule_id}/
+	OrganizationAlertRulesUrl = OrganizationsUrl + "%s/alert-rules/"
+	OrganizationAlertRuleUrl  = OrganizationAlertRulesUrl + "%s/"
+
+	// issue alert rules
+	//	projects/{organization_id_or_slug}/{project_id_or_slug}/rules/{rule_id}/
+	ProjectRulesUrl = ProjectsUrl + "rules/"
+	ProjectRuleUrl  = ProjectRulesUrl + "%s/"
+
+	// service hooks and legacy plugins, both send event data out of Sentry
+	//	projects/{organization_id_or_slug}/{project_id_or_slug}/hooks/{hook_id}/
+	//	projects/{organization_id_or_slug}/{project_id_or_slug}/plugins/{plugin_id}/
+	ProjectServiceHooksUrl = ProjectsUrl + "hooks/"
+	ProjectServiceHookUrl  = ProjectServiceHooksUrl + "%s/"
+	ProjectPluginUrl       = ProjectsUrl + "plugins/%s/"
+
+	// https://docs.sentry.io/api/integrations/delete-an-external-user/
+	//	organizations/{organization_id_or_slug}/external-users/{external_user_id}/
+	ExternalUserUrl = OrganizationsUrl + "%s/external-users/%s/"
+
+	// cron monitors
+	//	organizations/{organization_id_or_slug}/monitors/{monitor_id_or_slug}/
+	OrganizationMonitorsUrl = OrganizationsUrl + "%s/monitors/"
+	OrganizationMonitorUrl  = OrganizationMonitorsUrl + "%s/"
+
+	// uptime monitors are listed per organization and updated per project
+	//	organizations/{organization_id_or_slug}/uptime/
+	//	projects/{organization_id_or_slug}/{project_id_or_slug}/uptime/{uptime_project_subscription_id}/
+	OrganizationUptimeMonitorsUrl = OrganizationsUrl + "%s/uptime/"
+	ProjectUptimeMonitorUrl       = ProjectsUrl + "uptime/%s/"
+
+	// the organization's SSO configuration
+	//	organizations/{organization_id_or_slug}/auth-provider/
+	OrganizationAuthProviderUrl = OrganizationsUrl + "%s/auth-provider/"
+
+	// requests to join a team, approved or denied by team admins
+	//	organizations/{organization_id_or_slug}/access-requests/{request_id}/
+	OrganizationAccessRequestsUrl = OrganizationsUrl + "%s/access-requests/"
+	OrganizationAccessRequestUrl  = OrganizationAccessRequestsUrl + "%s/"
+
+	// requests to invite someone to the organization, approved or denied by managers
+	//	organizations/{organization_id_or_slug}/invite-requests/{member_id}/
+	OrganizationInviteRequestsUrl = OrganizationsUrl + "%s/invite-requests/"
+	OrganizationInviteRequestUrl  = OrganizationInviteRequestsUrl + "%s/"
+
+	// issue ownership rules, and the CODEOWNERS files imported from code mappings
+	//	projects/{organization_id_or_slug}/{project_id_or_slug}/ownership/
+	//	projects/{organization_id_or_slug}/{project_id_or_slug}/codeowners/
+	ProjectOwnershipUrl  = ProjectsUrl + "ownership/"
+	ProjectCodeOwnersUrl = ProjectsUrl + "codeowners/"
+
+	// SCIM endpoints, authenticated with the organization's SCIM token. Unlike the rest of the API their paths
+	// have no trailing slash.
+	//	organizations/{organization_id_or_slug}/scim/v2/Users/{member_id}
+	//	organizations/{organization_id_or_slug}/scim/v2/Groups/{team_id}
+	OrganizationSCIMUsersUrl  = OrganizationsUrl + "%s/scim/v2/Users"
+	OrganizationSCIMUserUrl   = OrganizationSCIMUsersUrl + "/%s"
+	OrganizationSCIMGroupsUrl = OrganizationsUrl + "%s/scim/v2/Groups"
+	OrganizationSCIMGroupUrl  = OrganizationSCIMGroupsUrl + "/%s"
+
+	//	organizations/{organization_id_or_slug}/dashboards/{dashboard_id}/
+	OrganizationDashboardsUrl = OrganizationsUrl + "%s/dashboards/"
+	OrganizationDashboardUrl  = OrganizationDashboardsUrl + "%s/"
 )
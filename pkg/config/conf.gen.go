@@ -5,6 +5,9 @@ import "reflect"
 
 type Sentry struct {
 	ApiToken string `mapstructure:"api-token"`
+	OrgTokens []string `mapstructure:"org-tokens"`
+	ScimTokens []string `mapstructure:"scim-tokens"`
+	DryRun bool `mapstructure:"dry-run"`
 }
 
 func (c* Sentry) findFieldByTag(tagValue string) (any, bool) {
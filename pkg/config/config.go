@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/conductorone/baton-sdk/pkg/field"
 )
 
@@ -14,7 +17,27 @@ var (
 		field.WithRequired(true),
 	)
 
-	ConfigurationFields = []field.SchemaField{ApiToken}
+	OrgTokens = field.StringSliceField(
+		"org-tokens",
+		field.WithDisplayName("Organization API Tokens"),
+		field.WithDescription("Additional API tokens for organizations that are only reachable with their own token, as org-slug=token pairs"),
+		field.WithIsSecret(true),
+	)
+
+	ScimTokens = field.StringSliceField(
+		"scim-tokens",
+		field.WithDisplayName("Organization SCIM Tokens"),
+		field.WithDescription("SCIM tokens for organizations provisioned through SAML and SCIM, as org-slug=token pairs. Account creation, removal and team membership changes for these organizations go through SCIM"),
+		field.WithIsSecret(true),
+	)
+
+	DryRun = field.BoolField(
+		"dry-run",
+		field.WithDisplayName("Dry Run"),
+		field.WithDescription("Log provisioning requests instead of sending them to Sentry"),
+	)
+
+	ConfigurationFields = []field.SchemaField{ApiToken, OrgTokens, ScimTokens, DryRun}
 
 	// FieldRelationships defines relationships between the ConfigurationFields that can be automatically validated.
 	// For example, a username and password can be required together, or an access token can be
@@ -28,3 +51,30 @@ var Config = field.NewConfiguration(
 	field.WithConstraints(FieldRelationships...),
 	field.WithConnectorDisplayName("Sentry"),
 )
+
+// ParseOrgTokens parses a list of org-slug=token pairs into a map keyed by organization slug.
+func ParseOrgTokens(pairs []string) (map[string]string, error) {
+	tokens := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		slug, token, ok := strings.Cut(pair, "=")
+		slug = strings.TrimSpace(slug)
+		token = strings.TrimSpace(token)
+		if !ok || slug == "" || token == "" {
+			return nil, fmt.Errorf("invalid organization token %q: expected org-slug=token", redactTokenPair(pair))
+		}
+		if _, exists := tokens[slug]; exists {
+			return nil, fmt.Errorf("duplicate token for organization %q", slug)
+		}
+		tokens[slug] = token
+	}
+	return tokens, nil
+}
+
+// redactTokenPair keeps the organization slug of a malformed pair so errors stay useful without leaking the token.
+func redactTokenPair(pair string) string {
+	slug, _, ok := strings.Cut(pair, "=")
+	if !ok {
+		return "<redacted>"
+	}
+	return slug + "=<redacted>"
+}
@@ -20,6 +20,22 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid config with organization tokens",
+			config: &Sentry{
+				ApiToken:  "asdfasdfaasdf",
+				OrgTokens: []string{"acme=qwerqwerqwer"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid config with SCIM tokens",
+			config: &Sentry{
+				ApiToken:   "asdfasdfaasdf",
+				ScimTokens: []string{"acme=zxcvzxcvzxcv"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "invalid config - missing required fields",
 			config: &Sentry{
@@ -40,3 +56,53 @@ func TestValidateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestParseOrgTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		pairs   []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:  "no tokens",
+			pairs: nil,
+			want:  map[string]string{},
+		},
+		{
+			name:  "multiple tokens",
+			pairs: []string{"acme=token-a", " widgets = token-b "},
+			want: map[string]string{
+				"acme":    "token-a",
+				"widgets": "token-b",
+			},
+		},
+		{
+			name:    "missing separator",
+			pairs:   []string{"acme"},
+			wantErr: true,
+		},
+		{
+			name:    "missing token",
+			pairs:   []string{"acme="},
+			wantErr: true,
+		},
+		{
+			name:    "duplicate organization",
+			pairs:   []string{"acme=token-a", "acme=token-b"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseOrgTokens(tt.pairs)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,450 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	config "github.com/conductorone/baton-sdk/pb/c1/config/v1"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/actions"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sentry/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const offboardMemberAction = "offboard_member"
+
+// The steps of the offboard_member action, in the order they run.
+const (
+	stepRemoveFromTeams     = "remove_from_teams"
+	stepReassignIssues      = "reassign_issues"
+	stepReassignAlertRules  = "reassign_alert_rules"
+	stepRemoveExternalUsers = "remove_external_users"
+	stepRemoveMembership    = "remove_membership"
+)
+
+// The states a step goes through. Finished steps report "complete: <what was done>" or "failed: <error>".
+const (
+	stepPending = "pending"
+	stepRunning = "running"
+	stepSkipped = "skipped"
+)
+
+var offboardMemberSchema = &v2.BatonActionSchema{
+	Name:        offboardMemberAction,
+	DisplayName: "Offboard member",
+	Description: "Removes a member from every team, hands their assigned issues and owned alert rules over to a team, " +
+		"removes their external user mappings and finally removes them from the organization. Only the issues seen in the last " +
+		client.IssueSearchPeriod + " are reassigned, older issues keep their assignee.",
+	Arguments: []*config.Field{
+		{
+			Name:        "member_id",
+			DisplayName: "Member ID",
+			Description: "The ID of the organization member to offboard.",
+			IsRequired:  true,
+			Field:       &config.Field_StringField{StringField: &config.StringField{}},
+		},
+		{
+			Name:        "reassign_to_team",
+			DisplayName: "Reassign to team",
+			Description: "The ID or slug of the team that takes over the member's issues and alert rules.",
+			IsRequired:  true,
+			Field:       &config.Field_StringField{StringField: &config.StringField{}},
+		},
+		{
+			Name:        "org_id",
+			DisplayName: "Organization ID",
+			Description: "The organization of the member, looked up from the member ID when empty.",
+			Field:       &config.Field_StringField{StringField: &config.StringField{}},
+		},
+	},
+	ReturnTypes: []*config.Field{
+		stepField(stepRemoveFromTeams, "Remove from teams"),
+		stepField(stepReassignIssues, "Reassign issues"),
+		stepField(stepReassignAlertRules, "Reassign alert rules"),
+		stepField(stepRemoveExternalUsers, "Remove external users"),
+		stepField(stepRemoveMembership, "Remove membership"),
+		{
+			Name:        "success",
+			DisplayName: "Success",
+			Description: "Whether every step completed.",
+			Field:       &config.Field_BoolField{BoolField: &config.BoolField{}},
+		},
+	},
+}
+
+func stepField(name, displayName string) *config.Field {
+	return &config.Field{
+		Name:        name,
+		DisplayName: displayName,
+		Description: "The state of the step: pending, running, skipped, complete or failed.",
+		Field:       &config.Field_StringField{StringField: &config.StringField{}},
+	}
+}
+
+// progressTTL is how long the progress of an action is kept when nobody polls it until it finishes.
+const progressTTL = time.Hour
+
+// actionManager runs the connector's custom actions. On top of the SDK's manager, it reports the progress of
+// each step while an action runs, GetActionStatus only has the result once the action finished otherwise.
+type actionManager struct {
+	*actions.ActionManager
+
+	mtx      sync.Mutex
+	progress map[string]*progress
+	// progressTTL expires the progress of actions never polled to completion.
+	progressTTL time.Duration
+}
+
+func newActionManager(ctx context.Context, c *client.Client) (*actionManager, error) {
+	m := &actionManager{
+		ActionManager: actions.NewActionManager(ctx),
+		progress:      map[string]*progress{},
+		progressTTL:   progressTTL,
+	}
+
+	o := &offboarder{client: c}
+	if err := m.RegisterAction(ctx, offboardMemberAction, offboardMemberSchema, o.offboard); err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to register %s action: %w", offboardMemberAction, err)
+	}
+
+	rm := &roleMigrator{client: c}
+	if err := m.RegisterAction(ctx, migrateRetiredRoleAction, migrateRetiredRoleSchema, rm.migrate); err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to register %s action: %w", migrateRetiredRoleAction, err)
+	}
+
+	r := &requestReviewer{client: c}
+	if err := r.register(ctx, m.ActionManager); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+func (m *actionManager) InvokeAction(ctx context.Context, name string, args *structpb.Struct) (string, v2.BatonActionStatus, *structpb.Struct, annotations.Annotations, error) {
+	p := &progress{states: map[string]string{}, startedAt: time.Now()}
+	// The SDK runs the handler with the context of the invocation, which ends as soon as the call returns
+	// while the action keeps running in the background.
+	ctx = context.WithValue(context.WithoutCancel(ctx), progressKey{}, p)
+
+	id, actionStatus, rv, annos, err := m.ActionManager.InvokeAction(ctx, name, args)
+	m.mtx.Lock()
+	m.expireProgress()
+	if id != "" && !isFinished(actionStatus) {
+		m.progress[id] = p
+	}
+	m.mtx.Unlock()
+
+	return id, actionStatus, rv, annos, err
+}
+
+func (m *actionManager) GetActionStatus(ctx context.Context, id string) (v2.BatonActionStatus, string, *structpb.Struct, annotations.Annotations, error) {
+	actionStatus, name, rv, annos, err := m.ActionManager.GetActionStatus(ctx, id)
+	if err != nil {
+		return actionStatus, name, rv, annos, err
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.expireProgress()
+	p, ok := m.progress[id]
+	if !ok {
+		return actionStatus, name, rv, annos, nil
+	}
+	if isFinished(actionStatus) {
+		delete(m.progress, id)
+		return actionStatus, name, rv, annos, nil
+	}
+
+	return actionStatus, name, p.snapshot(), annos, nil
+}
+
+// expireProgress drops the progress of the actions started more than progressTTL ago, m.mtx must be held.
+func (m *actionManager) expireProgress() {
+	for id, p := range m.progress {
+		if time.Since(p.startedAt) > m.progressTTL {
+			delete(m.progress, id)
+		}
+	}
+}
+
+func isFinished(actionStatus v2.BatonActionStatus) bool {
+	return actionStatus == v2.BatonActionStatus_BATON_ACTION_STATUS_COMPLETE ||
+		actionStatus == v2.BatonActionStatus_BATON_ACTION_STATUS_FAILED
+}
+
+type progressKey struct{}
+
+// progress holds the state of every step of a running action.
+type progress struct {
+	mtx       sync.Mutex
+	steps     []string
+	states    map[string]string
+	startedAt time.Time
+}
+
+// progressFromContext returns the progress of the action run with ctx, handlers called outside of
+// InvokeAction get one nobody reads.
+func progressFromContext(ctx context.Context) *progress {
+	if p, ok := ctx.Value(progressKey{}).(*progress); ok {
+		return p
+	}
+	return &progress{states: map[string]string{}}
+}
+
+func (p *progress) start(steps ...string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.steps = steps
+	for _, step := range steps {
+		p.states[step] = stepPending
+	}
+}
+
+func (p *progress) set(step, state string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+	p.states[step] = state
+}
+
+func (p *progress) snapshot() *structpb.Struct {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	fields := make(map[string]*structpb.Value, len(p.steps)+1)
+	for _, step := range p.steps {
+		fields[step] = structpb.NewStringValue(p.states[step])
+	}
+
+	return &structpb.Struct{Fields: fields}
+}
+
+func (p *progress) result(success bool) *structpb.Struct {
+	rv := p.snapshot()
+	rv.Fields["success"] = structpb.NewBoolValue(success)
+	return rv
+}
+
+func stringArg(args *structpb.Struct, name string) string {
+	return args.GetFields()[name].GetStringValue()
+}
+
+type offboarder struct {
+	client *client.Client
+}
+
+type offboardStep struct {
+	name string
+	run  func(ctx context.Context) (string, error)
+}
+
+// offboard runs the steps in order and stops at the first failure, so a member is never removed from the
+// organization while their issues or alert rules still point at them.
+func (o *offboarder) offboard(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	memberID := stringArg(args, "member_id")
+	if memberID == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "baton-sentry: member_id is required")
+	}
+	teamID := stringArg(args, "reassign_to_team")
+	if teamID == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "baton-sentry: reassign_to_team is required")
+	}
+
+	orgID := stringArg(args, "org_id")
+	if orgID == "" {
+		var err error
+		orgID, err = client.FindUserOrgID(ctx, o.client, memberID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-sentry: failed to find organization for user %s: %w", memberID, err)
+		}
+	}
+
+	member, _, err := o.client.GetOrganizationMember(ctx, orgID, memberID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to get organization member %s: %w", memberID, err)
+	}
+	if err := checkNotOnlyOwner(member, orgID); err != nil {
+		return nil, nil, err
+	}
+	if isManagedByIDP(ctx, o.client, orgID, member.Flags.IDPProvisioned) {
+		return nil, nil, managedByIDPError("member", memberID)
+	}
+	team, _, err := o.client.GetTeam(ctx, orgID, teamID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to get team %s: %w", teamID, err)
+	}
+
+	m := &offboarding{client: o.client, orgID: orgID, member: member, team: team}
+	steps := []offboardStep{
+		{stepRemoveFromTeams, m.removeFromTeams},
+		{stepReassignIssues, m.reassignIssues},
+		{stepReassignAlertRules, m.reassignAlertRules},
+		{stepRemoveExternalUsers, m.removeExternalUsers},
+		{stepRemoveMembership, m.removeMembership},
+	}
+
+	p := progressFromContext(ctx)
+	names := make([]string, 0, len(steps))
+	for _, step := range steps {
+		names = append(names, step.name)
+	}
+	p.start(names...)
+
+	for i, step := range steps {
+		p.set(step.name, stepRunning)
+		state, err := step.run(ctx)
+		if err != nil {
+			p.set(step.name, "failed: "+err.Error())
+			for _, next := range steps[i+1:] {
+				p.set(next.name, stepSkipped)
+			}
+			return p.result(false), nil, fmt.Errorf("baton-sentry: failed to offboard member %s: %s: %w", memberID, step.name, err)
+		}
+		p.set(step.name, state)
+	}
+
+	return p.result(true), simulatedAnnotations(simulations), nil
+}
+
+// offboarding is the member being offboarded and the team taking over what they own.
+type offboarding struct {
+	client *client.Client
+	orgID  string
+	member *client.DetailedMember
+	team   *client.Team
+}
+
+func (m *offboarding) removeFromTeams(ctx context.Context) (string, error) {
+	for _, teamSlug := range m.member.Teams {
+		err := removeTeamMember(ctx, m.client, m.orgID, teamSlug, m.member.ID)
+		if err != nil && !client.IsNotFound(err) {
+			return "", fmt.Errorf("failed to remove member from team %s: %w", teamSlug, err)
+		}
+	}
+
+	return fmt.Sprintf("complete: removed from %d teams", len(m.member.Teams)), nil
+}
+
+// reassignIssues hands the issues assigned to the member over to the team. Invites that were never accepted
+// have no user, so nothing can be assigned to them. Sentry only searches the issues seen within
+// client.IssueSearchPeriod, the step reports that window since older issues keep their assignee.
+func (m *offboarding) reassignIssues(ctx context.Context) (string, error) {
+	if m.member.User == nil {
+		return stepSkipped, nil
+	}
+
+	listIssues := func(ctx context.Context, opts client.PageOptions) (*client.Page[client.Issue], error) {
+		return m.client.ListIssues(ctx, m.orgID, "assigned:"+m.member.User.Email, opts)
+	}
+
+	var issueIDs []string
+	for issue, err := range client.All(ctx, client.MaxPerPage, listIssues) {
+		if err != nil {
+			return "", err
+		}
+		issueIDs = append(issueIDs, issue.ID)
+	}
+
+	if len(issueIDs) > 0 {
+		if err := m.client.AssignIssues(ctx, m.orgID, issueIDs, client.TeamActor(m.team.ID)); err != nil {
+			return "", err
+		}
+	}
+
+	return fmt.Sprintf("complete: reassigned %d issues seen in the last %s to %s", len(issueIDs), client.IssueSearchPeriod, m.team.Slug), nil
+}
+
+// reassignAlertRules hands the metric alert rules and the issue alert rules of every project owned by the
+// member over to the team.
+func (m *offboarding) reassignAlertRules(ctx context.Context) (string, error) {
+	if m.member.User == nil {
+		return stepSkipped, nil
+	}
+
+	owner := client.UserActor(m.member.User.ID)
+	newOwner := client.TeamActor(m.team.ID)
+	isOwned := func(rule client.AlertRule) bool {
+		return rule.Owner != nil && *rule.Owner == owner
+	}
+	reassigned := 0
+
+	listMetricRules := func(ctx context.Context, opts client.PageOptions) (*client.Page[client.AlertRule], error) {
+		return m.client.ListMetricAlertRules(ctx, m.orgID, opts)
+	}
+	for rule, err := range client.All(ctx, client.MaxPerPage, listMetricRules) {
+		if err != nil {
+			return "", err
+		}
+		if !isOwned(rule) {
+			continue
+		}
+		if err := m.client.SetMetricAlertRuleOwner(ctx, m.orgID, rule.ID, newOwner); err != nil {
+			return "", fmt.Errorf("failed to reassign metric alert rule %s: %w", rule.ID, err)
+		}
+		reassigned++
+	}
+
+	listProjects := func(ctx context.Context, opts client.PageOptions) (*client.Page[client.Project], error) {
+		return m.client.ListProjects(ctx, m.orgID, opts)
+	}
+	for project, err := range client.All(ctx, client.MaxPerPage, listProjects) {
+		if err != nil {
+			return "", err
+		}
+
+		listIssueRules := func(ctx context.Context, opts client.PageOptions) (*client.Page[client.AlertRule], error) {
+			return m.client.ListIssueAlertRules(ctx, m.orgID, project.Slug, opts)
+		}
+		for rule, err := range client.All(ctx, client.MaxPerPage, listIssueRules) {
+			if err != nil {
+				return "", err
+			}
+			if !isOwned(rule) {
+				continue
+			}
+			if err := m.client.SetIssueAlertRuleOwner(ctx, m.orgID, project.Slug, rule.ID, newOwner); err != nil {
+				return "", fmt.Errorf("failed to reassign issue alert rule %s: %w", rule.ID, err)
+			}
+			reassigned++
+		}
+	}
+
+	return fmt.Sprintf("complete: reassigned %d alert rules to %s", reassigned, m.team.Slug), nil
+}
+
+func (m *offboarding) removeExternalUsers(ctx context.Context) (string, error) {
+	if m.member.User == nil {
+		return stepSkipped, nil
+	}
+
+	externalUsers, err := m.client.ListMemberExternalUsers(ctx, m.orgID, m.member.ID)
+	if err != nil {
+		return "", err
+	}
+
+	for _, externalUser := range externalUsers {
+		err := m.client.DeleteExternalUser(ctx, m.orgID, externalUser.ID)
+		if err != nil && !client.IsNotFound(err) {
+			return "", fmt.Errorf("failed to remove %s external user %s: %w", externalUser.Provider, externalUser.ExternalName, err)
+		}
+	}
+
+	return fmt.Sprintf("complete: removed %d external users", len(externalUsers)), nil
+}
+
+func (m *offboarding) removeMembership(ctx context.Context) (string, error) {
+	err := removeMember(ctx, m.client, m.orgID, m.member.ID)
+	if err != nil && !client.IsNotFound(err) {
+		return "", err
+	}
+
+	return "complete: removed from the organization", nil
+}
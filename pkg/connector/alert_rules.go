@@ -0,0 +1,303 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+const alertRuleOwner = "owner"
+
+type alertRuleBuilder struct {
+	client    *client.Client
+	directory *directory
+
+	mtx sync.Mutex
+	// owners holds the owner of each listed rule by resource ID, nil for rules nobody owns, until its grants are read.
+	owners map[string]*string
+}
+
+func (o *alertRuleBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return alertRuleResourceType
+}
+
+// alertRuleRef identifies an alert rule, projectID is only set for issue alert rules.
+type alertRuleRef struct {
+	orgID     string
+	projectID string
+	ruleID    string
+}
+
+// parseAlertRuleID parses <orgID>/<ruleID> for metric alert rules and <orgID>/<projectID>/<ruleID> for
+// issue alert rules.
+func parseAlertRuleID(id string) (alertRuleRef, error) {
+	split := strings.Split(id, "/")
+	switch len(split) {
+	case 2:
+		return alertRuleRef{orgID: split[0], ruleID: split[1]}, nil
+	case 3:
+		return alertRuleRef{orgID: split[0], projectID: split[1], ruleID: split[2]}, nil
+	default:
+		return alertRuleRef{}, fmt.Errorf("baton-sentry: expected alert rule resource ID to be in the format 'orgId/ruleId' or 'orgId/projectId/ruleId', got %s", id)
+	}
+}
+
+func (r alertRuleRef) String() string {
+	if r.projectID == "" {
+		return fmt.Sprintf("%s/%s", r.orgID, r.ruleID)
+	}
+	return fmt.Sprintf("%s/%s/%s", r.orgID, r.projectID, r.ruleID)
+}
+
+func newAlertRuleResource(rule client.AlertRule, ref alertRuleRef, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	description := "Metric alert rule"
+	if ref.projectID != "" {
+		description = "Issue alert rule"
+	}
+
+	return resourceSdk.NewResource(
+		rule.Name,
+		alertRuleResourceType,
+		ref.String(),
+		resourceSdk.WithDescription(description),
+		resourceSdk.WithParentResourceID(parentResourceID),
+	)
+}
+
+// List returns the metric alert rules of an organization or the issue alert rules of a project.
+func (o *alertRuleBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	var cursor string
+	if pToken != nil {
+		cursor = pToken.Token
+	}
+
+	ref := alertRuleRef{orgID: parentResourceID.Resource}
+	var page *client.Page[client.AlertRule]
+	var err error
+	switch parentResourceID.ResourceType {
+	case organizationResourceType.Id:
+		page, err = o.client.ListMetricAlertRules(ctx, ref.orgID, client.PageOptions{Cursor: cursor})
+	case projectResourceType.Id:
+		ref.projectID = parentResourceID.Resource
+		ref.orgID, err = client.FindProjectOrgID(ctx, o.client, ref.projectID)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-sentry: failed to find organization for project %s: %w", ref.projectID, err)
+		}
+		page, err = o.client.ListIssueAlertRules(ctx, ref.orgID, ref.projectID, client.PageOptions{Cursor: cursor})
+	default:
+		return nil, "", nil, fmt.Errorf("baton-sentry: alert rules are not listed under %s", parentResourceID.ResourceType)
+	}
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var annotations annotations.Annotations
+	annotations = *annotations.WithRateLimiting(page.RateLimit)
+
+	ret := make([]*v2.Resource, 0, len(page.Items))
+	for _, rule := range page.Items {
+		ref.ruleID = rule.ID
+		resource, err := newAlertRuleResource(rule, ref, parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		o.rememberOwner(ref, rule.Owner)
+		ret = append(ret, resource)
+	}
+
+	return ret, page.NextCursor, annotations, nil
+}
+
+func (o *alertRuleBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	ref, err := parseAlertRuleID(resourceId.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rule, err := o.getRule(ctx, ref)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to get alert rule %s: %w", ref.ruleID, err)
+	}
+
+	parentResourceID := &v2.ResourceId{ResourceType: organizationResourceType.Id, Resource: ref.orgID}
+	if ref.projectID != "" {
+		parentResourceID = &v2.ResourceId{ResourceType: projectResourceType.Id, Resource: ref.projectID}
+	}
+
+	resource, err := newAlertRuleResource(*rule, ref, parentResourceID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource, nil, nil
+}
+
+func (o *alertRuleBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			alertRuleOwner,
+			entitlement.WithDescription(fmt.Sprintf("Owner of %s alert rule", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("Owner of %s alert rule", resource.DisplayName)),
+			entitlement.WithGrantableTo(userResourceType, teamResourceType),
+		),
+	}, "", nil, nil
+}
+
+// Grants returns the owner of the rule, a rule has at most one.
+func (o *alertRuleBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	ref, err := parseAlertRuleID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	owner, err := o.takeOwner(ctx, ref)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-sentry: failed to get alert rule %s: %w", ref.ruleID, err)
+	}
+	if owner == nil {
+		return nil, "", nil, nil
+	}
+
+	principalID, err := actorPrincipalID(ctx, o.directory, ref.orgID, *owner)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if principalID == nil {
+		return nil, "", nil, nil
+	}
+
+	return []*v2.Grant{grant.NewGrant(resource, alertRuleOwner, principalID)}, "", nil, nil
+}
+
+// Grant makes the principal the owner of the rule, replacing the previous owner.
+func (o *alertRuleBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	ref, err := parseAlertRuleID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := principalActor(ctx, o.client, ref.orgID, principal.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := o.currentOwner(ctx, ref)
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get alert rule %s: %w", ref.ruleID, err)
+	}
+	if owner != nil && *owner == actor {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	if err := o.setOwner(ctx, ref, actor); err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to set owner of alert rule %s: %w", ref.ruleID, err)
+	}
+
+	return simulatedAnnotations(simulations), nil
+}
+
+// Revoke leaves the rule without an owner, unless it was already handed over to someone else.
+func (o *alertRuleBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	ref, err := parseAlertRuleID(grant.Entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := principalActor(ctx, o.client, ref.orgID, grant.Principal.Id)
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := o.currentOwner(ctx, ref)
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get alert rule %s: %w", ref.ruleID, err)
+	}
+	if owner == nil || *owner != actor {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	if err := o.setOwner(ctx, ref, ""); err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to clear owner of alert rule %s: %w", ref.ruleID, err)
+	}
+
+	return simulatedAnnotations(simulations), nil
+}
+
+// currentOwner returns the owner of the rule as it is now in Sentry, Grant and Revoke can't trust a cached copy.
+func (o *alertRuleBuilder) rememberOwner(ref alertRuleRef, owner *string) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.owners[ref.String()] = owner
+}
+
+// takeOwner returns the owner found in the list of rules, so a sync doesn't fetch each rule. Rules that weren't listed
+// by this builder are fetched.
+func (o *alertRuleBuilder) takeOwner(ctx context.Context, ref alertRuleRef) (*string, error) {
+	o.mtx.Lock()
+	owner, ok := o.owners[ref.String()]
+	delete(o.owners, ref.String())
+	o.mtx.Unlock()
+	if ok {
+		return owner, nil
+	}
+
+	rule, err := o.getRule(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	return rule.Owner, nil
+}
+
+func (o *alertRuleBuilder) currentOwner(ctx context.Context, ref alertRuleRef) (*string, error) {
+	rule, err := o.getRule(client.WithoutCache(ctx), ref)
+	if err != nil {
+		return nil, err
+	}
+	return rule.Owner, nil
+}
+
+func (o *alertRuleBuilder) getRule(ctx context.Context, ref alertRuleRef) (*client.AlertRule, error) {
+	if ref.projectID == "" {
+		return o.client.GetMetricAlertRule(ctx, ref.orgID, ref.ruleID)
+	}
+	return o.client.GetIssueAlertRule(ctx, ref.orgID, ref.projectID, ref.ruleID)
+}
+
+func (o *alertRuleBuilder) setOwner(ctx context.Context, ref alertRuleRef, owner string) error {
+	if ref.projectID == "" {
+		return o.client.SetMetricAlertRuleOwner(ctx, ref.orgID, ref.ruleID, owner)
+	}
+	return o.client.SetIssueAlertRuleOwner(ctx, ref.orgID, ref.projectID, ref.ruleID, owner)
+}
+
+func newAlertRuleBuilder(client *client.Client) *alertRuleBuilder {
+	return &alertRuleBuilder{
+		client:    client,
+		directory: newDirectory(client),
+		owners:    map[string]*string{},
+	}
+}
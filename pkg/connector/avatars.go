@@ -0,0 +1,44 @@
+package connector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+var avatarUUIDPattern = regexp.MustCompile(`^[0-9a-fA-F-]+$`)
+
+// avatarAssetRef returns the asset reference for an uploaded avatar, or nil when the avatar
+// is a letter avatar or gravatar that Sentry doesn't serve.
+// Asset IDs have the form <kind>/<org ID>/<avatar uuid>, e.g. team-avatar/1/4d7a1f..., the organization
+// picks the credential the avatar is downloaded with.
+func avatarAssetRef(kind, orgID string, avatar client.Avatar) *v2.AssetRef {
+	if avatar.AvatarType != client.AvatarTypeUpload || avatar.AvatarUUID == nil || *avatar.AvatarUUID == "" {
+		return nil
+	}
+
+	return &v2.AssetRef{
+		Id: fmt.Sprintf("%s/%s/%s", kind, orgID, *avatar.AvatarUUID),
+	}
+}
+
+// parseAvatarAssetID returns the kind, organization and UUID of an avatar. Asset IDs of earlier syncs have no
+// organization, it is left empty.
+func parseAvatarAssetID(id string) (string, string, string, error) {
+	parts := strings.Split(id, "/")
+	var kind, orgID, avatarUUID string
+	switch len(parts) {
+	case 2:
+		kind, avatarUUID = parts[0], parts[1]
+	case 3:
+		kind, orgID, avatarUUID = parts[0], parts[1], parts[2]
+	}
+	if !avatarUUIDPattern.MatchString(avatarUUID) {
+		return "", "", "", fmt.Errorf("baton-sentry: invalid avatar asset ID %q", id)
+	}
+
+	return kind, orgID, avatarUUID, nil
+}
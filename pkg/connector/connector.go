@@ -2,6 +2,7 @@ package connector
 
 import (
 	"context"
+	"fmt"
 	"io"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -21,20 +22,43 @@ func (d *Connector) ResourceSyncers(_ context.Context) []connectorbuilder.Resour
 		newUserBuilder(d.client),
 		newTeamBuilder(d.client),
 		newProjectBuilder(d.client),
+		newAlertRuleBuilder(d.client),
+		newMonitorBuilder(d.client),
+		newDataForwarderBuilder(d.client),
+		newDashboardBuilder(d.client),
+		newAccessRequestBuilder(d.client),
+		newInviteRequestBuilder(d.client),
+		newRelayBuilder(d.client),
 	}
 }
 
 // Asset takes an input AssetRef and attempts to fetch it using the connector's authenticated http client
 // It streams a response, always starting with a metadata object, following by chunked payloads for the asset.
-func (d *Connector) Asset(_ context.Context, _ *v2.AssetRef) (string, io.ReadCloser, error) {
-	return "", nil, nil
+func (d *Connector) Asset(ctx context.Context, asset *v2.AssetRef) (string, io.ReadCloser, error) {
+	kind, orgID, avatarUUID, err := parseAvatarAssetID(asset.GetId())
+	if err != nil {
+		return "", nil, err
+	}
+
+	contentType, body, err := d.client.GetAvatar(ctx, orgID, kind, avatarUUID)
+	if err != nil {
+		return "", nil, fmt.Errorf("baton-sentry: failed to get avatar %s: %w", asset.GetId(), err)
+	}
+
+	return contentType, body, nil
+}
+
+// RegisterActionManager returns the manager running the connector's custom actions, such as offboard_member,
+// migrate_retired_role and the approval of pending team join and invite requests.
+func (d *Connector) RegisterActionManager(ctx context.Context) (connectorbuilder.CustomActionManager, error) {
+	return newActionManager(ctx, d.client)
 }
 
 // Metadata returns metadata about the connector.
 func (d *Connector) Metadata(_ context.Context) (*v2.ConnectorMetadata, error) {
 	return &v2.ConnectorMetadata{
 		DisplayName: "Baton Sentry Connector",
-		Description: "This connector integrates with Sentry to manage and sync organizations, users, teams, and projects.",
+		Description: "This connector integrates with Sentry to manage and sync organizations, users, teams, projects, alert rules, monitors, service hooks and plugins, dashboards, trusted relays, and pending team join and invite requests.",
 		AccountCreationSchema: &v2.ConnectorAccountCreationSchema{
 			FieldMap: map[string]*v2.ConnectorAccountCreationSchema_Field{
 				"email": {
@@ -79,8 +103,8 @@ func (d *Connector) Validate(_ context.Context) (annotations.Annotations, error)
 }
 
 // New returns a new instance of the connector.
-func New(ctx context.Context, apiToken string) (*Connector, error) {
-	client, err := client.New(ctx, apiToken)
+func New(ctx context.Context, apiToken string, opts ...client.Option) (*Connector, error) {
+	client, err := client.New(ctx, apiToken, opts...)
 	if err != nil {
 		return nil, err
 	}
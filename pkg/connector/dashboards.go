@@ -0,0 +1,280 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+const dashboardEditor = "editor"
+
+type dashboardBuilder struct {
+	client *client.Client
+}
+
+func (o *dashboardBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return dashboardResourceType
+}
+
+func newDashboardResource(dashboard client.Dashboard, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	return resourceSdk.NewResource(
+		dashboard.Title,
+		dashboardResourceType,
+		// <orgID>/<dashboardID>
+		fmt.Sprintf("%s/%s", parentResourceID.Resource, dashboard.ID),
+		resourceSdk.WithParentResourceID(parentResourceID),
+	)
+}
+
+func parseDashboardID(id string) (string, string, error) {
+	split := strings.Split(id, "/")
+	if len(split) != 2 {
+		return "", "", fmt.Errorf("baton-sentry: expected dashboard resource ID to be in the format 'orgId/dashboardId', got %s", id)
+	}
+	return split[0], split[1], nil
+}
+
+func (o *dashboardBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	var cursor string
+	if pToken != nil {
+		cursor = pToken.Token
+	}
+
+	page, err := o.client.ListDashboards(ctx, parentResourceID.Resource, client.PageOptions{Cursor: cursor})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var annotations annotations.Annotations
+	annotations = *annotations.WithRateLimiting(page.RateLimit)
+
+	ret := make([]*v2.Resource, 0, len(page.Items))
+	for _, dashboard := range page.Items {
+		// Prebuilt dashboards such as "default-overview" have no numeric ID and their edit access can't be changed.
+		if _, err := strconv.Atoi(dashboard.ID); err != nil {
+			continue
+		}
+
+		resource, err := newDashboardResource(dashboard, parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		ret = append(ret, resource)
+	}
+
+	return ret, page.NextCursor, annotations, nil
+}
+
+func (o *dashboardBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	orgID, dashboardID, err := parseDashboardID(resourceId.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dashboard, err := o.client.GetDashboard(ctx, orgID, dashboardID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to get dashboard %s: %w", dashboardID, err)
+	}
+
+	resource, err := newDashboardResource(*dashboard, &v2.ResourceId{
+		ResourceType: organizationResourceType.Id,
+		Resource:     orgID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource, nil, nil
+}
+
+// Entitlements returns the editor entitlement. Granting it to the organization lets every member edit the
+// dashboard, granting it to teams restricts editing to their members.
+func (o *dashboardBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{
+		entitlement.NewPermissionEntitlement(
+			resource,
+			dashboardEditor,
+			entitlement.WithDescription(fmt.Sprintf("Can edit %s dashboard", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("Editor of %s dashboard", resource.DisplayName)),
+			entitlement.WithGrantableTo(organizationResourceType, teamResourceType),
+		),
+	}, "", nil, nil
+}
+
+func (o *dashboardBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	orgID, dashboardID, err := parseDashboardID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	dashboard, err := o.client.GetDashboard(ctx, orgID, dashboardID)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-sentry: failed to get dashboard %s: %w", dashboardID, err)
+	}
+	permissions := dashboard.EditPermissions()
+
+	// Sentry keeps the team list of a dashboard every member can edit, but ignores it until the dashboard is
+	// restricted again, so only the organization has edit access.
+	if permissions.IsEditableByEveryone {
+		orgResourceID, err := resourceSdk.NewResourceID(organizationResourceType, orgID)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-sentry: failed to create resource ID for organization %s: %w", orgID, err)
+		}
+
+		return []*v2.Grant{
+			grant.NewGrant(
+				resource,
+				dashboardEditor,
+				orgResourceID,
+				grant.WithAnnotation(&v2.GrantExpandable{
+					EntitlementIds: []string{
+						fmt.Sprintf("organization:%s:%s", orgID, organizationMembership),
+					},
+					Shallow: true,
+				}),
+			),
+		}, "", nil, nil
+	}
+
+	ret := make([]*v2.Grant, 0, len(permissions.TeamsWithEditAccess))
+	for _, teamID := range permissions.TeamsWithEditAccess {
+		teamResourceID := fmt.Sprintf("%s/%d", orgID, teamID)
+		principalID, err := resourceSdk.NewResourceID(teamResourceType, teamResourceID)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-sentry: failed to create resource ID for team %d: %w", teamID, err)
+		}
+
+		ret = append(ret, grant.NewGrant(
+			resource,
+			dashboardEditor,
+			principalID,
+			grant.WithAnnotation(&v2.GrantExpandable{
+				EntitlementIds: []string{
+					fmt.Sprintf("team:%s:%s", teamResourceID, teamMembership),
+				},
+				Shallow: true,
+			}),
+		))
+	}
+
+	return ret, "", nil, nil
+}
+
+func (o *dashboardBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	orgID, dashboardID, err := parseDashboardID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	// The new permissions are built from the current ones, a cached copy would drop the changes made since.
+	dashboard, err := o.client.GetDashboard(client.WithoutCache(ctx), orgID, dashboardID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get dashboard %s: %w", dashboardID, err)
+	}
+	permissions := dashboard.EditPermissions()
+
+	switch principal.Id.ResourceType {
+	case organizationResourceType.Id:
+		if permissions.IsEditableByEveryone {
+			return annotations.New(&v2.GrantAlreadyExists{}), nil
+		}
+		permissions.IsEditableByEveryone = true
+	case teamResourceType.Id:
+		teamID, err := dashboardTeamID(principal.Id)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(permissions.TeamsWithEditAccess, teamID) {
+			return annotations.New(&v2.GrantAlreadyExists{}), nil
+		}
+		permissions.TeamsWithEditAccess = append(permissions.TeamsWithEditAccess, teamID)
+	default:
+		return nil, fmt.Errorf("baton-sentry: expected principal to be an organization or a team, got %s", principal.Id.ResourceType)
+	}
+
+	if err := o.client.SetDashboardPermissions(ctx, orgID, dashboardID, permissions); err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to update dashboard permissions: %w", err)
+	}
+
+	return simulatedAnnotations(simulations), nil
+}
+
+func (o *dashboardBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	orgID, dashboardID, err := parseDashboardID(grant.Entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard, err := o.client.GetDashboard(client.WithoutCache(ctx), orgID, dashboardID)
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get dashboard %s: %w", dashboardID, err)
+	}
+	permissions := dashboard.EditPermissions()
+
+	switch grant.Principal.Id.ResourceType {
+	case organizationResourceType.Id:
+		if !permissions.IsEditableByEveryone {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		permissions.IsEditableByEveryone = false
+	case teamResourceType.Id:
+		teamID, err := dashboardTeamID(grant.Principal.Id)
+		if err != nil {
+			return nil, err
+		}
+		if !slices.Contains(permissions.TeamsWithEditAccess, teamID) {
+			return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+		}
+		permissions.TeamsWithEditAccess = slices.DeleteFunc(permissions.TeamsWithEditAccess, func(id int) bool { return id == teamID })
+	default:
+		return nil, fmt.Errorf("baton-sentry: expected principal to be an organization or a team, got %s", grant.Principal.Id.ResourceType)
+	}
+
+	if err := o.client.SetDashboardPermissions(ctx, orgID, dashboardID, permissions); err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to update dashboard permissions: %w", err)
+	}
+
+	return simulatedAnnotations(simulations), nil
+}
+
+// dashboardTeamID returns the numeric team ID Sentry uses in dashboard permissions.
+func dashboardTeamID(principalID *v2.ResourceId) (int, error) {
+	split := strings.Split(principalID.Resource, "/")
+	if len(split) != 2 {
+		return 0, fmt.Errorf("baton-sentry: expected team resource ID to be in the format 'orgId/teamId', got %s", principalID.Resource)
+	}
+
+	teamID, err := strconv.Atoi(split[1])
+	if err != nil {
+		return 0, fmt.Errorf("baton-sentry: expected a numeric team ID, got %s", split[1])
+	}
+
+	return teamID, nil
+}
+
+func newDashboardBuilder(client *client.Client) *dashboardBuilder {
+	return &dashboardBuilder{
+		client: client,
+	}
+}
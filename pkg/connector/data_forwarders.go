@@ -0,0 +1,261 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+const (
+	serviceHookForwarder = "hook"
+	pluginForwarder      = "plugin"
+)
+
+type dataForwarderBuilder struct {
+	client *client.Client
+}
+
+func (o *dataForwarderBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return dataForwarderResourceType
+}
+
+// dataForwarderRef identifies a service hook by ID or a plugin by slug, within their project.
+type dataForwarderRef struct {
+	orgID     string
+	projectID string
+	kind      string
+	id        string
+}
+
+// parseDataForwarderID parses <orgID>/<projectID>/hook/<hookID> and <orgID>/<projectID>/plugin/<pluginSlug>.
+func parseDataForwarderID(id string) (dataForwarderRef, error) {
+	split := strings.Split(id, "/")
+	if len(split) != 4 || (split[2] != serviceHookForwarder && split[2] != pluginForwarder) {
+		return dataForwarderRef{}, fmt.Errorf("baton-sentry: expected data forwarder resource ID to be in the format 'orgId/projectId/hook/hookId' or 'orgId/projectId/plugin/pluginSlug', got %s", id)
+	}
+	return dataForwarderRef{orgID: split[0], projectID: split[1], kind: split[2], id: split[3]}, nil
+}
+
+func (r dataForwarderRef) String() string {
+	return fmt.Sprintf("%s/%s/%s/%s", r.orgID, r.projectID, r.kind, r.id)
+}
+
+// redactTargetURL strips the credentials a target URL can carry, in its userinfo or its query string, before it is
+// exposed. URLs that don't parse are left out.
+func redactTargetURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.ForceQuery = false
+	u.Fragment = ""
+	u.RawFragment = ""
+	return u.String()
+}
+
+func newServiceHookResource(hook client.ServiceHook, ref dataForwarderRef, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ref.kind = serviceHookForwarder
+	ref.id = hook.ID
+	targetURL := redactTargetURL(hook.URL)
+	profile := map[string]interface{}{
+		"org_id":      ref.orgID,
+		"project_id":  ref.projectID,
+		"kind":        serviceHookForwarder,
+		"target_url":  targetURL,
+		"events":      strings.Join(hook.Events, ","),
+		"status":      hook.Status,
+		"create_date": hook.DateCreated.Format(time.RFC3339),
+	}
+
+	displayName := targetURL
+	if displayName == "" {
+		displayName = fmt.Sprintf("Service hook %s", hook.ID)
+	}
+
+	return resourceSdk.NewAppResource(
+		displayName,
+		dataForwarderResourceType,
+		ref.String(),
+		[]resourceSdk.AppTraitOption{resourceSdk.WithAppProfile(profile)},
+		resourceSdk.WithDescription(fmt.Sprintf("Service hook sending %s events to %s", strings.Join(hook.Events, ", "), displayName)),
+		resourceSdk.WithParentResourceID(parentResourceID),
+	)
+}
+
+func newPluginResource(plugin client.Plugin, ref dataForwarderRef, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ref.kind = pluginForwarder
+	ref.id = plugin.Slug
+	var targetURLs []string
+	for _, targetURL := range client.PluginTargetURLs(plugin) {
+		if targetURL = redactTargetURL(targetURL); targetURL != "" {
+			targetURLs = append(targetURLs, targetURL)
+		}
+	}
+
+	status := "disabled"
+	if plugin.Enabled {
+		status = "enabled"
+	}
+	profile := map[string]interface{}{
+		"org_id":      ref.orgID,
+		"project_id":  ref.projectID,
+		"kind":        pluginForwarder,
+		"plugin_type": plugin.Type,
+		"target_url":  strings.Join(targetURLs, ","),
+		"status":      status,
+	}
+	// Sentry reports the release status of the plugin itself, such as beta, apart from whether it is enabled.
+	if plugin.Status != "" {
+		profile["plugin_status"] = plugin.Status
+	}
+
+	description := fmt.Sprintf("%s plugin", plugin.Name)
+	if len(targetURLs) > 0 {
+		description = fmt.Sprintf("%s plugin sending events to %s", plugin.Name, strings.Join(targetURLs, ", "))
+	}
+
+	return resourceSdk.NewAppResource(
+		plugin.Name,
+		dataForwarderResourceType,
+		ref.String(),
+		[]resourceSdk.AppTraitOption{resourceSdk.WithAppProfile(profile)},
+		resourceSdk.WithDescription(description),
+		resourceSdk.WithParentResourceID(parentResourceID),
+	)
+}
+
+// List returns the service hooks of the project, then its enabled plugins.
+func (o *dataForwarderBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentResourceID == nil || parentResourceID.ResourceType != projectResourceType.Id {
+		return nil, "", nil, nil
+	}
+
+	var token string
+	if pToken != nil {
+		token = pToken.Token
+	}
+
+	bag := &pagination.Bag{}
+	if err := bag.Unmarshal(token); err != nil {
+		return nil, "", nil, err
+	}
+	if bag.Current() == nil {
+		bag.Push(pagination.PageState{ResourceTypeID: pluginForwarder})
+		bag.Push(pagination.PageState{ResourceTypeID: serviceHookForwarder})
+	}
+
+	ref := dataForwarderRef{projectID: parentResourceID.Resource}
+	var err error
+	ref.orgID, err = client.FindProjectOrgID(ctx, o.client, ref.projectID)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-sentry: failed to find organization for project %s: %w", ref.projectID, err)
+	}
+
+	var ret []*v2.Resource
+	var nextCursor string
+	var annotations annotations.Annotations
+	switch bag.ResourceTypeID() {
+	case serviceHookForwarder:
+		page, err := o.client.ListServiceHooks(ctx, ref.orgID, ref.projectID, client.PageOptions{Cursor: bag.PageToken()})
+		if err != nil {
+			return nil, "", nil, err
+		}
+		annotations = *annotations.WithRateLimiting(page.RateLimit)
+		nextCursor = page.NextCursor
+
+		for _, hook := range page.Items {
+			resource, err := newServiceHookResource(hook, ref, parentResourceID)
+			if err != nil {
+				return nil, "", nil, err
+			}
+			ret = append(ret, resource)
+		}
+	case pluginForwarder:
+		project, _, err := o.client.GetProject(ctx, ref.orgID, ref.projectID)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-sentry: failed to get project: %w", err)
+		}
+
+		// Every available plugin is listed, only the enabled ones send data anywhere.
+		for _, plugin := range project.Plugins {
+			if !plugin.Enabled {
+				continue
+			}
+			detailed, err := o.client.GetPlugin(ctx, ref.orgID, ref.projectID, plugin.Slug)
+			if err != nil {
+				return nil, "", nil, fmt.Errorf("baton-sentry: failed to get plugin %s: %w", plugin.Slug, err)
+			}
+
+			resource, err := newPluginResource(*detailed, ref, parentResourceID)
+			if err != nil {
+				return nil, "", nil, err
+			}
+			ret = append(ret, resource)
+		}
+	default:
+		return nil, "", nil, fmt.Errorf("baton-sentry: unexpected data forwarder page state %s", bag.ResourceTypeID())
+	}
+
+	next, err := bag.NextToken(nextCursor)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return ret, next, annotations, nil
+}
+
+func (o *dataForwarderBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	ref, err := parseDataForwarderID(resourceId.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parentResourceID := &v2.ResourceId{ResourceType: projectResourceType.Id, Resource: ref.projectID}
+
+	var resource *v2.Resource
+	if ref.kind == serviceHookForwarder {
+		hook, err := o.client.GetServiceHook(ctx, ref.orgID, ref.projectID, ref.id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-sentry: failed to get service hook %s: %w", ref.id, err)
+		}
+		resource, err = newServiceHookResource(*hook, ref, parentResourceID)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		plugin, err := o.client.GetPlugin(ctx, ref.orgID, ref.projectID, ref.id)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-sentry: failed to get plugin %s: %w", ref.id, err)
+		}
+		resource, err = newPluginResource(*plugin, ref, parentResourceID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return resource, nil, nil
+}
+
+func (o *dataForwarderBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func (o *dataForwarderBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newDataForwarderBuilder(client *client.Client) *dataForwarderBuilder {
+	return &dataForwarderBuilder{
+		client: client,
+	}
+}
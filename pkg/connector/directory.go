@@ -0,0 +1,95 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+// directoryTTL is how long the members and teams of an organization are reused. They are looked up for every
+// project, alert rule and monitor of a sync, but a long running connector must still notice changes.
+const directoryTTL = 10 * time.Minute
+
+// orgDirectory indexes the members and teams of an organization, to resolve the users and teams that Sentry
+// refers to by email, user ID or slug.
+type orgDirectory struct {
+	loadedAt time.Time
+	// membersByEmail maps lower cased emails to member IDs, both the invite and the account email of a member.
+	membersByEmail map[string]string
+	// membersByUserID maps user IDs to member IDs, invites have no user yet.
+	membersByUserID map[string]string
+	teamsBySlug     map[string]string
+}
+
+// directory builds the orgDirectory of each organization once and shares it between calls.
+type directory struct {
+	client *client.Client
+
+	mtx  sync.Mutex
+	orgs map[string]*orgDirectory
+}
+
+func newDirectory(c *client.Client) *directory {
+	return &directory{
+		client: c,
+		orgs:   map[string]*orgDirectory{},
+	}
+}
+
+func (d *directory) get(ctx context.Context, orgID string) (*orgDirectory, error) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	if org, ok := d.orgs[orgID]; ok && time.Since(org.loadedAt) < directoryTTL {
+		return org, nil
+	}
+
+	org, err := d.load(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	d.orgs[orgID] = org
+
+	return org, nil
+}
+
+func (d *directory) load(ctx context.Context, orgID string) (*orgDirectory, error) {
+	listMembers := func(ctx context.Context, opts client.PageOptions) (*client.Page[client.OrganizationMember], error) {
+		return d.client.ListOrganizationMembers(ctx, orgID, opts)
+	}
+	listTeams := func(ctx context.Context, opts client.PageOptions) (*client.Page[client.Team], error) {
+		return d.client.ListTeams(ctx, orgID, opts)
+	}
+
+	org := &orgDirectory{
+		loadedAt:        time.Now(),
+		membersByEmail:  map[string]string{},
+		membersByUserID: map[string]string{},
+		teamsBySlug:     map[string]string{},
+	}
+	for member, err := range client.All(ctx, client.MaxPerPage, listMembers) {
+		if err != nil {
+			return nil, fmt.Errorf("baton-sentry: failed to list organization members: %w", err)
+		}
+		org.membersByEmail[strings.ToLower(member.Email)] = member.ID
+		if member.User != nil {
+			org.membersByUserID[member.User.ID] = member.ID
+			if member.User.Email != "" {
+				org.membersByEmail[strings.ToLower(member.User.Email)] = member.ID
+			}
+		}
+	}
+
+	for team, err := range client.All(ctx, client.MaxPerPage, listTeams) {
+		if err != nil {
+			return nil, fmt.Errorf("baton-sentry: failed to list teams: %w", err)
+		}
+		org.teamsBySlug[team.Slug] = team.ID
+	}
+
+	return org, nil
+}
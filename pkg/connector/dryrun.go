@@ -0,0 +1,28 @@
+package connector
+
+import (
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	sentryv1 "github.com/conductorone/baton-sentry/pb/sentry/v1"
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+// simulatedAnnotations marks a provisioning call as simulated when dry-run mode skipped its requests,
+// listing the requests that would have been sent.
+func simulatedAnnotations(simulations *client.Simulations) annotations.Annotations {
+	simulated := simulations.Requests()
+	if len(simulated) == 0 {
+		return nil
+	}
+
+	requests := make([]*sentryv1.DryRun_Request, 0, len(simulated))
+	for _, req := range simulated {
+		requests = append(requests, &sentryv1.DryRun_Request{
+			Action: req.Action,
+			Method: req.Method,
+			Url:    req.URL,
+			Body:   req.Body,
+		})
+	}
+
+	return annotations.New(&sentryv1.DryRun{Requests: requests})
+}
@@ -0,0 +1,33 @@
+package connector
+
+import (
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// managedByIDPError explains why Sentry refuses to change members and teams provisioned through SCIM, they
+// have to be changed in the identity provider instead.
+func managedByIDPError(kind, id string) error {
+	return status.Errorf(codes.FailedPrecondition,
+		"baton-sentry: %s %s is managed by the identity provider and must be changed there", kind, id)
+}
+
+// isIDPProvisioned reads the idp_provisioned flag from the profile of a user or team resource. Resources
+// synced before the flag was kept report false, Sentry's refusal is caught when the change is sent.
+func isIDPProvisioned(resource *v2.Resource) bool {
+	if resource == nil {
+		return false
+	}
+
+	var profile *structpb.Struct
+	if groupTrait, err := resourceSdk.GetGroupTrait(resource); err == nil {
+		profile = groupTrait.GetProfile()
+	} else if userTrait, err := resourceSdk.GetUserTrait(resource); err == nil {
+		profile = userTrait.GetProfile()
+	}
+
+	return profile.GetFields()["idp_provisioned"].GetBoolValue()
+}
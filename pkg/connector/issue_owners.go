@@ -0,0 +1,150 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sentry/pkg/client"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// projectIssueOwner is granted to the users and teams that a project's ownership rules or CODEOWNERS files
+// assign issues to. It follows the rules, so it can't be granted or revoked.
+const projectIssueOwner = "issue_owner"
+
+// projectOwnership is the ownership configuration of a project with its imported CODEOWNERS files.
+type projectOwnership struct {
+	rules      client.ProjectOwnership
+	codeowners []client.CodeOwners
+}
+
+func getProjectOwnership(ctx context.Context, c *client.Client, orgID, projectID string) (*projectOwnership, error) {
+	rules, err := c.GetProjectOwnership(ctx, orgID, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get ownership rules of project %s: %w", projectID, err)
+	}
+
+	// Projects without a code mapping have no CODEOWNERS to list, and plans without the feature can't list them.
+	codeowners, err := c.ListProjectCodeOwners(ctx, orgID, projectID)
+	if err != nil && !client.IsNotFound(err) && !client.IsForbidden(err) {
+		return nil, fmt.Errorf("baton-sentry: failed to list codeowners of project %s: %w", projectID, err)
+	}
+
+	return &projectOwnership{
+		rules:      *rules,
+		codeowners: codeowners,
+	}, nil
+}
+
+// owners returns the users (by email) and teams (by "#<team slug>") referenced by the rules.
+func (p *projectOwnership) owners() []string {
+	raw := []string{p.rules.Raw}
+	for _, codeowners := range p.codeowners {
+		raw = append(raw, codeowners.OwnershipSyntax)
+	}
+	return client.OwnershipRuleOwners(strings.Join(raw, "\n"))
+}
+
+// addProfile adds the rules to a project profile. Owners that have no issue_owner grant are users who are no
+// longer members of the organization, or deleted teams.
+func (p *projectOwnership) addProfile(profile map[string]interface{}) {
+	codeowners := make([]interface{}, 0, len(p.codeowners))
+	for _, c := range p.codeowners {
+		codeowners = append(codeowners, c.OwnershipSyntax)
+	}
+	owners := []interface{}{}
+	for _, owner := range p.owners() {
+		owners = append(owners, owner)
+	}
+
+	profile["ownership_rules"] = p.rules.Raw
+	profile["ownership_fallthrough"] = p.rules.FallThrough
+	profile["ownership_auto_assignment"] = p.rules.AutoAssignment
+	profile["codeowners"] = codeowners
+	profile["issue_owners"] = owners
+}
+
+// issueOwnerGrants resolves the owners referenced by the rules to users and teams of the organization.
+func issueOwnerGrants(ctx context.Context, dir *directory, resource *v2.Resource, orgID string, ownership *projectOwnership) ([]*v2.Grant, error) {
+	owners := ownership.owners()
+	if len(owners) == 0 {
+		return nil, nil
+	}
+
+	org, err := dir.get(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ret []*v2.Grant
+	// Emails are matched case insensitively, the same member may be referenced more than once.
+	granted := map[string]bool{}
+	for _, owner := range owners {
+		if slug, ok := client.OwnerTeamSlug(owner); ok {
+			teamID, ok := org.teamsBySlug[slug]
+			if !ok {
+				ctxzap.Extract(ctx).Warn("baton-sentry: issue owner team does not exist",
+					zap.String("project_id", resource.Id.Resource),
+					zap.String("owner", owner),
+				)
+				continue
+			}
+
+			teamResourceID := fmt.Sprintf("%s/%s", orgID, teamID)
+			if granted["team:"+teamResourceID] {
+				continue
+			}
+			granted["team:"+teamResourceID] = true
+
+			principalID, err := resourceSdk.NewResourceID(teamResourceType, teamResourceID)
+			if err != nil {
+				return nil, fmt.Errorf("baton-sentry: failed to create resource ID for team %s: %w", teamID, err)
+			}
+			ret = append(ret, grant.NewGrant(
+				resource,
+				projectIssueOwner,
+				principalID,
+				grant.WithAnnotation(&v2.GrantExpandable{
+					EntitlementIds: []string{
+						fmt.Sprintf("team:%s:%s", teamResourceID, teamMembership),
+					},
+					Shallow: true,
+				}),
+			))
+			continue
+		}
+
+		memberID, ok := org.membersByEmail[strings.ToLower(owner)]
+		if !ok {
+			ctxzap.Extract(ctx).Warn("baton-sentry: issue owner is not a member of the organization",
+				zap.String("project_id", resource.Id.Resource),
+				zap.String("owner", owner),
+			)
+			continue
+		}
+
+		if granted["user:"+memberID] {
+			continue
+		}
+		granted["user:"+memberID] = true
+
+		principalID, err := resourceSdk.NewResourceID(userResourceType, memberID)
+		if err != nil {
+			return nil, fmt.Errorf("baton-sentry: failed to create resource ID for member %s: %w", memberID, err)
+		}
+		ret = append(ret, grant.NewGrant(resource, projectIssueOwner, principalID))
+	}
+
+	return ret, nil
+}
+
+// isIssueOwnerEntitlement tells the issue_owner entitlement apart by its ID, grants only carry the ID of
+// their entitlement.
+func isIssueOwnerEntitlement(e *v2.Entitlement) bool {
+	return strings.HasSuffix(e.Id, ":"+projectIssueOwner)
+}
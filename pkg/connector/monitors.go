@@ -0,0 +1,349 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+const monitorOwner = "owner"
+
+const (
+	cronMonitor   = "cron"
+	uptimeMonitor = "uptime"
+)
+
+type monitorBuilder struct {
+	client    *client.Client
+	directory *directory
+
+	mtx sync.Mutex
+	// owners holds the owner of each listed monitor by resource ID, nil for monitors nobody owns, until its grants
+	// are read.
+	owners map[string]*client.Actor
+}
+
+func (o *monitorBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return monitorResourceType
+}
+
+// monitorRef identifies a monitor. Cron monitors are addressed by slug within the organization, uptime
+// monitors by ID within their project.
+type monitorRef struct {
+	orgID       string
+	kind        string
+	projectSlug string
+	monitorID   string
+}
+
+// parseMonitorID parses <orgID>/cron/<monitorSlug> and <orgID>/uptime/<projectSlug>/<monitorID>.
+func parseMonitorID(id string) (monitorRef, error) {
+	split := strings.Split(id, "/")
+	switch {
+	case len(split) == 3 && split[1] == cronMonitor:
+		return monitorRef{orgID: split[0], kind: cronMonitor, monitorID: split[2]}, nil
+	case len(split) == 4 && split[1] == uptimeMonitor:
+		return monitorRef{orgID: split[0], kind: uptimeMonitor, projectSlug: split[2], monitorID: split[3]}, nil
+	default:
+		return monitorRef{}, fmt.Errorf("baton-sentry: expected monitor resource ID to be in the format 'orgId/cron/monitorSlug' or 'orgId/uptime/projectSlug/monitorId', got %s", id)
+	}
+}
+
+func (r monitorRef) String() string {
+	if r.kind == uptimeMonitor {
+		return fmt.Sprintf("%s/%s/%s/%s", r.orgID, r.kind, r.projectSlug, r.monitorID)
+	}
+	return fmt.Sprintf("%s/%s/%s", r.orgID, r.kind, r.monitorID)
+}
+
+func newCronMonitorResource(monitor client.Monitor, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ref := monitorRef{orgID: parentResourceID.Resource, kind: cronMonitor, monitorID: monitor.Slug}
+
+	return resourceSdk.NewResource(
+		monitor.Name,
+		monitorResourceType,
+		ref.String(),
+		resourceSdk.WithDescription(fmt.Sprintf("Cron monitor of project %s", monitor.Project.Slug)),
+		resourceSdk.WithParentResourceID(parentResourceID),
+	)
+}
+
+func newUptimeMonitorResource(monitor client.UptimeMonitor, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	ref := monitorRef{orgID: parentResourceID.Resource, kind: uptimeMonitor, projectSlug: monitor.ProjectSlug, monitorID: monitor.ID}
+
+	return resourceSdk.NewResource(
+		monitor.Name,
+		monitorResourceType,
+		ref.String(),
+		resourceSdk.WithDescription(fmt.Sprintf("Uptime monitor of %s in project %s", monitor.URL, monitor.ProjectSlug)),
+		resourceSdk.WithParentResourceID(parentResourceID),
+	)
+}
+
+// List returns the cron monitors of the organization, then its uptime monitors.
+func (o *monitorBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	var token string
+	if pToken != nil {
+		token = pToken.Token
+	}
+
+	bag := &pagination.Bag{}
+	if err := bag.Unmarshal(token); err != nil {
+		return nil, "", nil, err
+	}
+	if bag.Current() == nil {
+		bag.Push(pagination.PageState{ResourceTypeID: uptimeMonitor})
+		bag.Push(pagination.PageState{ResourceTypeID: cronMonitor})
+	}
+
+	orgID := parentResourceID.Resource
+	opts := client.PageOptions{Cursor: bag.PageToken()}
+
+	var ret []*v2.Resource
+	var nextCursor string
+	var annotations annotations.Annotations
+	switch bag.ResourceTypeID() {
+	case cronMonitor:
+		page, err := o.client.ListMonitors(ctx, orgID, opts)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		annotations = *annotations.WithRateLimiting(page.RateLimit)
+		nextCursor = page.NextCursor
+
+		for _, monitor := range page.Items {
+			resource, err := newCronMonitorResource(monitor, parentResourceID)
+			if err != nil {
+				return nil, "", nil, err
+			}
+			o.rememberOwner(resource.Id.Resource, monitor.Owner)
+			ret = append(ret, resource)
+		}
+	case uptimeMonitor:
+		page, err := o.client.ListUptimeMonitors(ctx, orgID, opts)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		annotations = *annotations.WithRateLimiting(page.RateLimit)
+		nextCursor = page.NextCursor
+
+		for _, monitor := range page.Items {
+			resource, err := newUptimeMonitorResource(monitor, parentResourceID)
+			if err != nil {
+				return nil, "", nil, err
+			}
+			o.rememberOwner(resource.Id.Resource, monitor.Owner)
+			ret = append(ret, resource)
+		}
+	default:
+		return nil, "", nil, fmt.Errorf("baton-sentry: unexpected monitor page state %s", bag.ResourceTypeID())
+	}
+
+	next, err := bag.NextToken(nextCursor)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	return ret, next, annotations, nil
+}
+
+func (o *monitorBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	ref, err := parseMonitorID(resourceId.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parentResourceID := &v2.ResourceId{ResourceType: organizationResourceType.Id, Resource: ref.orgID}
+
+	var resource *v2.Resource
+	if ref.kind == cronMonitor {
+		monitor, err := o.client.GetMonitor(ctx, ref.orgID, ref.monitorID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-sentry: failed to get monitor %s: %w", ref.monitorID, err)
+		}
+		resource, err = newCronMonitorResource(*monitor, parentResourceID)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		monitor, err := o.client.GetUptimeMonitor(ctx, ref.orgID, ref.projectSlug, ref.monitorID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-sentry: failed to get uptime monitor %s: %w", ref.monitorID, err)
+		}
+		resource, err = newUptimeMonitorResource(*monitor, parentResourceID)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return resource, nil, nil
+}
+
+func (o *monitorBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(
+			resource,
+			monitorOwner,
+			entitlement.WithDescription(fmt.Sprintf("Owner of %s monitor", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("Owner of %s monitor", resource.DisplayName)),
+			entitlement.WithGrantableTo(userResourceType, teamResourceType),
+		),
+	}, "", nil, nil
+}
+
+// Grants returns the owner of the monitor, a monitor has at most one.
+func (o *monitorBuilder) Grants(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	ref, err := parseMonitorID(resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	owner, err := o.takeOwner(ctx, ref)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if owner == nil {
+		return nil, "", nil, nil
+	}
+
+	principalID, err := actorPrincipalID(ctx, o.directory, ref.orgID, owner.String())
+	if err != nil {
+		return nil, "", nil, err
+	}
+	if principalID == nil {
+		return nil, "", nil, nil
+	}
+
+	return []*v2.Grant{grant.NewGrant(resource, monitorOwner, principalID)}, "", nil, nil
+}
+
+// Grant makes the principal the owner of the monitor, replacing the previous owner.
+func (o *monitorBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	ref, err := parseMonitorID(entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := principalActor(ctx, o.client, ref.orgID, principal.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A cached copy could still show the owner from before the last change.
+	owner, err := o.owner(client.WithoutCache(ctx), ref)
+	if err != nil {
+		return nil, err
+	}
+	if owner != nil && owner.String() == actor {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	if err := o.setOwner(ctx, ref, actor); err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to set owner of monitor %s: %w", ref.monitorID, err)
+	}
+
+	return simulatedAnnotations(simulations), nil
+}
+
+// Revoke leaves the monitor without an owner, unless it was already handed over to someone else.
+func (o *monitorBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	ref, err := parseMonitorID(grant.Entitlement.Resource.Id.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	actor, err := principalActor(ctx, o.client, ref.orgID, grant.Principal.Id)
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := o.owner(client.WithoutCache(ctx), ref)
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if owner == nil || owner.String() != actor {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+
+	if err := o.setOwner(ctx, ref, ""); err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to clear owner of monitor %s: %w", ref.monitorID, err)
+	}
+
+	return simulatedAnnotations(simulations), nil
+}
+
+func (o *monitorBuilder) rememberOwner(resourceID string, owner *client.Actor) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	if o.owners == nil {
+		o.owners = make(map[string]*client.Actor)
+	}
+	o.owners[resourceID] = owner
+}
+
+// takeOwner returns the owner found in the list of monitors, so a sync doesn't fetch each monitor. Monitors that
+// weren't listed by this builder are fetched.
+func (o *monitorBuilder) takeOwner(ctx context.Context, ref monitorRef) (*client.Actor, error) {
+	o.mtx.Lock()
+	owner, ok := o.owners[ref.String()]
+	delete(o.owners, ref.String())
+	o.mtx.Unlock()
+	if ok {
+		return owner, nil
+	}
+
+	return o.owner(ctx, ref)
+}
+
+func (o *monitorBuilder) owner(ctx context.Context, ref monitorRef) (*client.Actor, error) {
+	if ref.kind == cronMonitor {
+		monitor, err := o.client.GetMonitor(ctx, ref.orgID, ref.monitorID)
+		if err != nil {
+			return nil, fmt.Errorf("baton-sentry: failed to get monitor %s: %w", ref.monitorID, err)
+		}
+		return monitor.Owner, nil
+	}
+
+	monitor, err := o.client.GetUptimeMonitor(ctx, ref.orgID, ref.projectSlug, ref.monitorID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get uptime monitor %s: %w", ref.monitorID, err)
+	}
+	return monitor.Owner, nil
+}
+
+func (o *monitorBuilder) setOwner(ctx context.Context, ref monitorRef, owner string) error {
+	if ref.kind == cronMonitor {
+		return o.client.SetMonitorOwner(ctx, ref.orgID, ref.monitorID, owner)
+	}
+	return o.client.SetUptimeMonitorOwner(ctx, ref.orgID, ref.projectSlug, ref.monitorID, owner)
+}
+
+func newMonitorBuilder(client *client.Client) *monitorBuilder {
+	return &monitorBuilder{
+		client:    client,
+		directory: newDirectory(client),
+	}
+}
@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -11,6 +12,8 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/conductorone/baton-sentry/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const organizationMembership = "member"
@@ -23,31 +26,50 @@ func (o *organizationBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 	return organizationResourceType
 }
 
-func newOrgResource(org client.Organization) (*v2.Resource, error) {
+// newOrgResource builds the organization resource, its profile carries the security settings of the
+// organization. authProvider is nil when the organization has no SSO.
+func newOrgResource(org client.Organization, authProvider *client.AuthProvider) (*v2.Resource, error) {
 	profile := map[string]interface{}{
-		"status": org.Status.Name,
+		"status":                        org.Status.Name,
+		"has_auth_provider":             org.HasAuthProvider,
+		"require_2fa":                   org.Require2FA,
+		"allow_member_invite":           org.AllowMemberInvite,
+		"allow_member_project_creation": org.AllowMemberProjectCreation,
+		"allow_superuser_access":        org.AllowSuperuserAccess,
 	}
+	if authProvider != nil {
+		profile["auth_provider"] = authProvider.ProviderName
+		profile["sso_default_role"] = authProvider.DefaultRole
+		profile["sso_required"] = authProvider.RequireLink
+		profile["scim_enabled"] = authProvider.ScimEnabled
+	}
+	groupTraitOptions := []resourceSdk.GroupTraitOption{
+		resourceSdk.WithGroupProfile(profile),
+	}
+	if icon := avatarAssetRef(client.OrganizationAvatar, org.ID, org.Avatar); icon != nil {
+		groupTraitOptions = append(groupTraitOptions, resourceSdk.WithGroupIcon(icon))
+	}
+
 	return resourceSdk.NewGroupResource(
 		org.Name, organizationResourceType,
 		org.ID,
-		[]resourceSdk.GroupTraitOption{
-			resourceSdk.WithGroupProfile(profile),
-		},
+		groupTraitOptions,
 		resourceSdk.WithAnnotation(
 			&v2.ChildResourceType{ResourceTypeId: userResourceType.Id},
 			&v2.ChildResourceType{ResourceTypeId: teamResourceType.Id},
 			&v2.ChildResourceType{ResourceTypeId: projectResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: alertRuleResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: monitorResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: dashboardResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: accessRequestResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: inviteRequestResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: relayResourceType.Id},
 		),
 	)
 }
 
-func (o *organizationBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
-	cursor := ""
-	if pToken != nil {
-		cursor = pToken.Token
-	}
-
-	orgs, res, ratelimitDescription, err := o.client.ListOrganizations(ctx, cursor)
+func (o *organizationBuilder) List(ctx context.Context, _ *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	orgs, ratelimitDescription, err := o.client.ListOrganizations(ctx)
 	if err != nil {
 		return nil, "", nil, fmt.Errorf("baton-sentry: failed to list organizations: %w", err)
 	}
@@ -56,30 +78,60 @@ func (o *organizationBuilder) List(ctx context.Context, _ *v2.ResourceId, pToken
 
 	ret := make([]*v2.Resource, 0, len(orgs))
 	for _, org := range orgs {
-		resource, err := newOrgResource(org)
+		authProvider, err := o.authProvider(ctx, org)
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		resource, err := newOrgResource(org, authProvider)
 		if err != nil {
 			return nil, "", nil, fmt.Errorf("baton-sentry: failed to create resource for organization %s: %w", org.ID, err)
 		}
 		ret = append(ret, resource)
 	}
 
-	nextCursor := ""
-	if client.HasNextPage(res) {
-		nextCursor = client.NextCursor(res)
+	return ret, "", annotations, nil
+}
+
+func (o *organizationBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	org, _, err := o.client.GetOrganization(ctx, resourceId.Resource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to get organization %s: %w", resourceId.Resource, err)
+	}
+
+	authProvider, err := o.authProvider(ctx, *org)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resource, err := newOrgResource(*org, authProvider)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to create resource for organization %s: %w", org.ID, err)
 	}
 
-	return ret, nextCursor, annotations, nil
+	return resource, nil, nil
 }
 
-func (o *organizationBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
-	return []*v2.Entitlement{
+// Entitlements returns the membership of the organization, an entitlement for each organization role and one for each
+// scope those roles give.
+func (o *organizationBuilder) Entitlements(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	org, _, err := o.client.GetOrganization(ctx, resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-sentry: failed to get organization %s: %w", resource.Id.Resource, err)
+	}
+
+	ret := []*v2.Entitlement{
 		entitlement.NewAssignmentEntitlement(
 			resource,
 			organizationMembership,
 			entitlement.WithDescription(fmt.Sprintf("Member of %s organization", resource.DisplayName)),
 			entitlement.WithDisplayName(fmt.Sprintf("Member of %s organization", resource.DisplayName)),
 		),
-	}, "", nil, nil
+	}
+	ret = append(ret, orgRoleEntitlements(resource, org.OrgRoleList)...)
+	ret = append(ret, scopeEntitlements(resource, org)...)
+
+	return ret, "", nil, nil
 }
 
 func (o *organizationBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
@@ -88,30 +140,139 @@ func (o *organizationBuilder) Grants(ctx context.Context, resource *v2.Resource,
 		cursor = pToken.Token
 	}
 
-	members, res, ratelimitDescription, err := o.client.ListOrganizationMembers(ctx, resource.Id.Resource, cursor)
+	page, err := o.client.ListOrganizationMembers(ctx, resource.Id.Resource, client.PageOptions{Cursor: cursor})
 	if err != nil {
 		return nil, "", nil, err
 	}
 
 	var annotations annotations.Annotations
-	annotations = *annotations.WithRateLimiting(ratelimitDescription)
+	annotations = *annotations.WithRateLimiting(page.RateLimit)
+
+	var ret []*v2.Grant
+	// The scope grants don't depend on the members, they come with the first page.
+	if cursor == "" {
+		org, _, err := o.client.GetOrganization(ctx, resource.Id.Resource)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-sentry: failed to get organization %s: %w", resource.Id.Resource, err)
+		}
+		ret = append(ret, scopeGrants(resource, org)...)
+	}
 
-	ret := make([]*v2.Grant, 0, len(members))
-	for _, member := range members {
+	for _, member := range page.Items {
 		resourceId, err := resourceSdk.NewResourceID(userResourceType, member.ID)
 		if err != nil {
 			return nil, "", nil, fmt.Errorf("baton-sentry: failed to create resource ID for user %s: %w", member.ID, err)
 		}
 
 		ret = append(ret, grant.NewGrant(resource, organizationMembership, resourceId))
+		// Invites get their role once accepted.
+		if !member.Pending && member.OrgRole != "" {
+			ret = append(ret, grant.NewGrant(resource, orgRoleSlug(member.OrgRole), resourceId))
+		}
+	}
+
+	return ret, page.NextCursor, annotations, nil
+}
+
+// Grant changes the organization role of a member. Only roles can be granted, members join by creating an account
+// and the scopes follow their role.
+func (o *organizationBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	roleID, ok := entitlementOrgRole(entitlement)
+	if !ok || principal.Id.ResourceType != userResourceType.Id {
+		return nil, fmt.Errorf("baton-sentry: only organization roles can be granted, and only to users")
 	}
 
-	var nextCursor string
-	if client.HasNextPage(res) {
-		nextCursor = client.NextCursor(res)
+	orgID := entitlement.Resource.Id.Resource
+	memberID := principal.Id.Resource
+	member, _, err := o.client.GetOrganizationMember(client.WithoutCache(ctx), orgID, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get organization member %s: %w", memberID, err)
+	}
+	if member.OrgRole == roleID {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+
+	if err := o.setOrgRole(ctx, orgID, member, roleID); err != nil {
+		return nil, err
+	}
+
+	return simulatedAnnotations(simulations), nil
+}
+
+// Revoke moves a member back to the member role, the role every member holds at least.
+func (o *organizationBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	roleID, ok := entitlementOrgRole(grant.Entitlement)
+	if !ok {
+		return nil, fmt.Errorf("baton-sentry: only organization roles can be revoked")
+	}
+
+	orgID := grant.Entitlement.Resource.Id.Resource
+	memberID := grant.Principal.Id.Resource
+	member, _, err := o.client.GetOrganizationMember(client.WithoutCache(ctx), orgID, memberID)
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get organization member %s: %w", memberID, err)
+	}
+	if member.OrgRole != roleID {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+	if roleID == client.OrgRoleMember {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"baton-sentry: every member of organization %s holds at least the member role, remove member %s from the organization instead", orgID, memberID)
+	}
+
+	if err := o.setOrgRole(ctx, orgID, member, client.OrgRoleMember); err != nil {
+		return nil, err
+	}
+
+	return simulatedAnnotations(simulations), nil
+}
+
+// setOrgRole changes the role of a member to a current role. The only owner of the organization keeps their role.
+func (o *organizationBuilder) setOrgRole(ctx context.Context, orgID string, member *client.DetailedMember, roleID string) error {
+	i := slices.IndexFunc(member.OrgRoleList, func(role client.OrganizationRole) bool { return role.ID == roleID })
+	if i < 0 || member.OrgRoleList[i].IsRetired {
+		return status.Errorf(codes.InvalidArgument, "baton-sentry: %s is not a current role of organization %s", roleID, orgID)
+	}
+	if err := checkNotOnlyOwner(member, orgID); err != nil {
+		return err
+	}
+	if member.Flags.IDPRoleRestricted {
+		return managedByIDPError("member role", member.ID)
+	}
+
+	err := o.client.UpdateMemberOrgRole(ctx, orgID, member.ID, roleID)
+	if client.IsManagedByIdentityProvider(err) {
+		return managedByIDPError("member role", member.ID)
+	}
+	if err != nil {
+		return fmt.Errorf("baton-sentry: failed to change role of member %s to %s: %w", member.ID, roleID, err)
+	}
+
+	return nil
+}
+
+// authProvider returns the SSO configuration of the organization, it is only looked up when Sentry says there is one.
+func (o *organizationBuilder) authProvider(ctx context.Context, org client.Organization) (*client.AuthProvider, error) {
+	if !org.HasAuthProvider {
+		return nil, nil
+	}
+
+	authProvider, err := o.client.GetAuthProvider(ctx, org.ID)
+	if client.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get auth provider of organization %s: %w", org.ID, err)
 	}
 
-	return ret, nextCursor, annotations, nil
+	return authProvider, nil
 }
 
 func newOrganizationBuilder(client *client.Client) *organizationBuilder {
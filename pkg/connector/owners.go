@@ -0,0 +1,74 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sentry/pkg/client"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// principalActor returns the actor Sentry uses for a user or team principal, such as the owner of an alert
+// rule or a monitor. Users are referred to by their user ID, so invites that were never accepted can't own
+// anything.
+func principalActor(ctx context.Context, c *client.Client, orgID string, principalID *v2.ResourceId) (string, error) {
+	switch principalID.ResourceType {
+	case teamResourceType.Id:
+		split := strings.Split(principalID.Resource, "/")
+		if len(split) != 2 {
+			return "", fmt.Errorf("baton-sentry: expected team resource ID to be in the format 'orgId/teamId', got %s", principalID.Resource)
+		}
+		return client.TeamActor(split[1]), nil
+	case userResourceType.Id:
+		member, _, err := c.GetOrganizationMember(ctx, orgID, principalID.Resource)
+		if err != nil {
+			return "", fmt.Errorf("baton-sentry: failed to get organization member: %w", err)
+		}
+		if member.User == nil {
+			return "", status.Errorf(codes.FailedPrecondition, "baton-sentry: member %s has not accepted their invite and can't own anything", member.ID)
+		}
+		return client.UserActor(member.User.ID), nil
+	default:
+		return "", fmt.Errorf("baton-sentry: expected principal to be a user or a team, got %s", principalID.ResourceType)
+	}
+}
+
+// actorPrincipalID returns the user or team resource of an actor. It returns nil for users who are no longer
+// members of the organization, what they owned is effectively unowned.
+func actorPrincipalID(ctx context.Context, dir *directory, orgID, actor string) (*v2.ResourceId, error) {
+	var principalID *v2.ResourceId
+	var err error
+
+	kind, actorID, _ := strings.Cut(actor, ":")
+	switch kind {
+	case "team":
+		principalID, err = resourceSdk.NewResourceID(teamResourceType, fmt.Sprintf("%s/%s", orgID, actorID))
+	case "user":
+		org, dirErr := dir.get(ctx, orgID)
+		if dirErr != nil {
+			return nil, dirErr
+		}
+		memberID, ok := org.membersByUserID[actorID]
+		if !ok {
+			ctxzap.Extract(ctx).Warn("baton-sentry: owner is not a member of the organization",
+				zap.String("org_id", orgID),
+				zap.String("owner", actor),
+			)
+			return nil, nil
+		}
+		principalID, err = resourceSdk.NewResourceID(userResourceType, memberID)
+	default:
+		return nil, fmt.Errorf("baton-sentry: unexpected owner %s", actor)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to create resource ID for owner %s: %w", actor, err)
+	}
+
+	return principalID, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -17,28 +18,47 @@ import (
 const projectAssignment = "assigned"
 
 type projectBuilder struct {
-	client *client.Client
+	client    *client.Client
+	directory *directory
+
+	mtx sync.Mutex
+	// ownership holds the ownership read while listing each project, until its grants use it.
+	ownership map[string]*projectOwnership
 }
 
 func (o *projectBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 	return projectResourceType
 }
 
-func newProjectResource(project client.Project, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+// newProjectResource builds the project resource, its profile carries a summary of the ownership rules unless
+// ownership is nil.
+func newProjectResource(project client.Project, ownership *projectOwnership, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
 	profile := map[string]interface{}{
 		"org_id":    parentResourceID.Resource,
 		"team_id":   project.ID,
 		"is_public": project.IsPublic,
 		"status":    project.Status,
 	}
+	if ownership != nil {
+		ownership.addProfile(profile)
+	}
+	groupTraitOptions := []resourceSdk.GroupTraitOption{
+		resourceSdk.WithGroupProfile(profile),
+	}
+	if icon := avatarAssetRef(client.ProjectAvatar, parentResourceID.Resource, project.Avatar); icon != nil {
+		groupTraitOptions = append(groupTraitOptions, resourceSdk.WithGroupIcon(icon))
+	}
+
 	return resourceSdk.NewGroupResource(
 		project.Name,
 		projectResourceType,
 		project.ID,
-		[]resourceSdk.GroupTraitOption{
-			resourceSdk.WithGroupProfile(profile),
-		},
+		groupTraitOptions,
 		resourceSdk.WithParentResourceID(parentResourceID),
+		resourceSdk.WithAnnotation(
+			&v2.ChildResourceType{ResourceTypeId: alertRuleResourceType.Id},
+			&v2.ChildResourceType{ResourceTypeId: dataForwarderResourceType.Id},
+		),
 	)
 }
 
@@ -53,29 +73,58 @@ func (o *projectBuilder) List(ctx context.Context, parentResourceID *v2.Resource
 	}
 
 	orgID := parentResourceID.Resource
-	projects, res, ratelimitDescription, err := o.client.ListProjects(ctx, orgID, cursor)
+	page, err := o.client.ListProjects(ctx, orgID, client.PageOptions{Cursor: cursor})
 	if err != nil {
 		return nil, "", nil, err
 	}
 
 	var annotations annotations.Annotations
-	annotations = *annotations.WithRateLimiting(ratelimitDescription)
+	annotations = *annotations.WithRateLimiting(page.RateLimit)
 
-	ret := make([]*v2.Resource, 0, len(projects))
-	for _, project := range projects {
-		resource, err := newProjectResource(project, parentResourceID)
+	ret := make([]*v2.Resource, 0, len(page.Items))
+	for _, project := range page.Items {
+		ownership, err := getProjectOwnership(ctx, o.client, orgID, project.ID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		o.rememberOwnership(project.ID, ownership)
+
+		resource, err := newProjectResource(project, ownership, parentResourceID)
 		if err != nil {
 			return nil, "", nil, err
 		}
 		ret = append(ret, resource)
 	}
 
-	nextCursor := ""
-	if client.HasNextPage(res) {
-		nextCursor = client.NextCursor(res)
+	return ret, page.NextCursor, annotations, nil
+}
+
+// Get looks up the project's organization when the parent isn't given, project IDs are unique across organizations.
+func (o *projectBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, parentResourceId *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	if parentResourceId == nil {
+		orgID, err := client.FindProjectOrgID(ctx, o.client, resourceId.Resource)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-sentry: failed to find organization for project %s: %w", resourceId.Resource, err)
+		}
+		parentResourceId = &v2.ResourceId{ResourceType: organizationResourceType.Id, Resource: orgID}
+	}
+
+	project, _, err := o.client.GetProject(ctx, parentResourceId.Resource, resourceId.Resource)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to get project: %w", err)
+	}
+
+	ownership, err := getProjectOwnership(ctx, o.client, parentResourceId.Resource, resourceId.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resource, err := newProjectResource(project.Project(), ownership, parentResourceId)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return ret, nextCursor, annotations, nil
+	return resource, nil, nil
 }
 
 func (o *projectBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
@@ -87,6 +136,14 @@ func (o *projectBuilder) Entitlements(_ context.Context, resource *v2.Resource,
 			entitlement.WithDisplayName(fmt.Sprintf("Assignment of %s project", resource.DisplayName)),
 			entitlement.WithGrantableTo(teamResourceType),
 		),
+		entitlement.NewPermissionEntitlement(
+			resource,
+			projectIssueOwner,
+			entitlement.WithDescription(fmt.Sprintf("Assigned issues of %s project by its ownership rules", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("Issue owner of %s project", resource.DisplayName)),
+			entitlement.WithGrantableTo(userResourceType, teamResourceType),
+			entitlement.WithAnnotation(&v2.EntitlementImmutable{}),
+		),
 	}, "", nil, nil
 }
 
@@ -118,10 +175,46 @@ func (o *projectBuilder) Grants(ctx context.Context, resource *v2.Resource, pTok
 		))
 	}
 
+	ownership, err := o.takeOwnership(ctx, orgID, resource.Id.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	ownerGrants, err := issueOwnerGrants(ctx, o.directory, resource, orgID, ownership)
+	if err != nil {
+		return nil, "", nil, err
+	}
+	ret = append(ret, ownerGrants...)
+
 	return ret, "", nil, nil
 }
 
+func (o *projectBuilder) rememberOwnership(projectID string, ownership *projectOwnership) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.ownership[projectID] = ownership
+}
+
+// takeOwnership returns the ownership read while listing the project, so a sync reads it once. Projects that weren't
+// listed by this builder have theirs read now.
+func (o *projectBuilder) takeOwnership(ctx context.Context, orgID, projectID string) (*projectOwnership, error) {
+	o.mtx.Lock()
+	ownership, ok := o.ownership[projectID]
+	delete(o.ownership, projectID)
+	o.mtx.Unlock()
+	if ok {
+		return ownership, nil
+	}
+
+	return getProjectOwnership(ctx, o.client, orgID, projectID)
+}
+
 func (o *projectBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	if isIssueOwnerEntitlement(entitlement) {
+		return nil, fmt.Errorf("baton-sentry: issue owners follow the project's ownership rules and can't be granted")
+	}
+
 	if principal.Id.ResourceType != teamResourceType.Id {
 		return nil, fmt.Errorf("baton-sentry: expected principal to be a team, got %s", principal.Id.ResourceType)
 	}
@@ -147,14 +240,23 @@ func (o *projectBuilder) Grant(ctx context.Context, principal *v2.Resource, enti
 	}
 
 	_, err = o.client.AddTeamToProject(ctx, orgId, projectId, teamId)
+	if client.IsConflict(err) {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("baton-sentry: failed to add team to project: %w", err)
 	}
 
-	return nil, nil
+	return simulatedAnnotations(simulations), nil
 }
 
 func (o *projectBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	if isIssueOwnerEntitlement(grant.Entitlement) {
+		return nil, fmt.Errorf("baton-sentry: issue owners follow the project's ownership rules and can't be revoked")
+	}
+
 	if grant.Principal.Id.ResourceType != teamResourceType.Id {
 		return nil, fmt.Errorf("baton-sentry: expected principal to be a team, got %s", grant.Principal.Id.ResourceType)
 	}
@@ -165,6 +267,9 @@ func (o *projectBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotatio
 	projectId := grant.Entitlement.Resource.Id.Resource
 
 	project, _, err := o.client.GetProject(ctx, orgId, projectId)
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("baton-sentry: failed to get project: %w", err)
 	}
@@ -182,15 +287,20 @@ func (o *projectBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotatio
 	}
 
 	_, err = o.client.DeleteTeamFromProject(ctx, orgId, projectId, teamId)
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("baton-sentry: failed to delete team from project: %w", err)
 	}
 
-	return nil, nil
+	return simulatedAnnotations(simulations), nil
 }
 
 func newProjectBuilder(client *client.Client) *projectBuilder {
 	return &projectBuilder{
-		client: client,
+		client:    client,
+		directory: newDirectory(client),
+		ownership: map[string]*projectOwnership{},
 	}
 }
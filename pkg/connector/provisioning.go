@@ -0,0 +1,58 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+// Organizations with a SCIM token are provisioned through their SCIM endpoints, which can change the members
+// and teams the identity provider manages. The others go through the member API.
+
+// addTeamMember adds a member to a team, team is the ID of the team.
+func addTeamMember(ctx context.Context, c *client.Client, orgID, teamID, memberID string) error {
+	if c.HasSCIM(ctx, orgID) {
+		return c.AddSCIMGroupMember(ctx, orgID, teamID, memberID)
+	}
+
+	_, err := c.AddOrgMemberToTeam(ctx, orgID, memberID, teamID)
+	return err
+}
+
+// removeTeamMember removes a member from a team, team is the ID or the slug of the team.
+func removeTeamMember(ctx context.Context, c *client.Client, orgID, team, memberID string) error {
+	if !c.HasSCIM(ctx, orgID) {
+		_, err := c.DeleteOrgMemberFromTeam(ctx, orgID, memberID, team)
+		return err
+	}
+
+	// SCIM groups are addressed by team ID only.
+	teamID := team
+	if _, err := strconv.Atoi(team); err != nil {
+		t, _, err := c.GetTeam(ctx, orgID, team)
+		if err != nil {
+			return fmt.Errorf("failed to get team %s: %w", team, err)
+		}
+		teamID = t.ID
+	}
+
+	return c.RemoveSCIMGroupMember(ctx, orgID, teamID, memberID)
+}
+
+// removeMember removes a member from the organization. Through SCIM the member is deactivated, which Sentry
+// treats as a removal.
+func removeMember(ctx context.Context, c *client.Client, orgID, memberID string) error {
+	if c.HasSCIM(ctx, orgID) {
+		return c.DeactivateSCIMUser(ctx, orgID, memberID)
+	}
+
+	return c.DeleteMemberFromOrganization(ctx, orgID, memberID)
+}
+
+// isManagedByIDP reports whether Sentry refuses to change something the identity provider provisioned,
+// which is only the case outside of SCIM.
+func isManagedByIDP(ctx context.Context, c *client.Client, orgID string, provisioned bool) bool {
+	return provisioned && !c.HasSCIM(ctx, orgID)
+}
@@ -0,0 +1,144 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sentry/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type relayBuilder struct {
+	client *client.Client
+}
+
+func (o *relayBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return relayResourceType
+}
+
+func newRelayResource(relay client.TrustedRelay, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	name := relay.Name
+	if name == "" {
+		name = relay.PublicKey
+	}
+
+	description := fmt.Sprintf("Public key %s", relay.PublicKey)
+	if relay.Description != "" {
+		description = fmt.Sprintf("%s, public key %s", relay.Description, relay.PublicKey)
+	}
+
+	return resourceSdk.NewSecretResource(
+		name,
+		relayResourceType,
+		// <orgID>/<publicKey>
+		fmt.Sprintf("%s/%s", parentResourceID.Resource, relay.PublicKey),
+		[]resourceSdk.SecretTraitOption{resourceSdk.WithSecretCreatedAt(relay.Created)},
+		resourceSdk.WithParentResourceID(parentResourceID),
+		resourceSdk.WithDescription(description),
+	)
+}
+
+func parseRelayID(id string) (string, string, error) {
+	orgID, publicKey, ok := strings.Cut(id, "/")
+	if !ok || orgID == "" || publicKey == "" {
+		return "", "", fmt.Errorf("baton-sentry: expected relay resource ID to be in the format 'orgId/publicKey', got %s", id)
+	}
+	return orgID, publicKey, nil
+}
+
+// List returns the trusted relays of the organization, they all come with the organization details.
+func (o *relayBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	relays, err := o.client.ListTrustedRelays(ctx, parentResourceID.Resource)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-sentry: failed to list trusted relays: %w", err)
+	}
+
+	ret := make([]*v2.Resource, 0, len(relays))
+	for _, relay := range relays {
+		resource, err := newRelayResource(relay, parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		ret = append(ret, resource)
+	}
+
+	return ret, "", nil, nil
+}
+
+func (o *relayBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	orgID, publicKey, err := parseRelayID(resourceId.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	relays, err := o.client.ListTrustedRelays(ctx, orgID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to list trusted relays: %w", err)
+	}
+	i := slices.IndexFunc(relays, func(relay client.TrustedRelay) bool { return relay.PublicKey == publicKey })
+	if i < 0 {
+		return nil, nil, status.Errorf(codes.NotFound, "baton-sentry: relay %s is not trusted by organization %s", publicKey, orgID)
+	}
+
+	resource, err := newRelayResource(relays[i], &v2.ResourceId{
+		ResourceType: organizationResourceType.Id,
+		Resource:     orgID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource, nil, nil
+}
+
+func (o *relayBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func (o *relayBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+// Delete stops trusting the relay. Sentry only updates the whole list, so it is written back without the relay.
+// The list is read past the HTTP cache, a cached copy would bring back relays deleted since it was read.
+func (o *relayBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	orgID, publicKey, err := parseRelayID(resourceId.Resource)
+	if err != nil {
+		return nil, err
+	}
+
+	relays, err := o.client.ListTrustedRelays(client.WithoutCache(ctx), orgID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to list trusted relays: %w", err)
+	}
+	remaining := slices.DeleteFunc(slices.Clone(relays), func(relay client.TrustedRelay) bool { return relay.PublicKey == publicKey })
+	if len(remaining) == len(relays) {
+		return nil, nil
+	}
+
+	err = o.client.SetTrustedRelays(ctx, orgID, remaining)
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to remove relay %s from organization %s: %w", publicKey, orgID, err)
+	}
+
+	return simulatedAnnotations(simulations), nil
+}
+
+func newRelayBuilder(client *client.Client) *relayBuilder {
+	return &relayBuilder{
+		client: client,
+	}
+}
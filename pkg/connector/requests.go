@@ -0,0 +1,313 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	config "github.com/conductorone/baton-sdk/pb/c1/config/v1"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/actions"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/pagination"
+	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
+	"github.com/conductorone/baton-sentry/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	approveAccessRequestAction = "approve_access_request"
+	denyAccessRequestAction    = "deny_access_request"
+	approveInviteRequestAction = "approve_invite_request"
+	denyInviteRequestAction    = "deny_invite_request"
+)
+
+type accessRequestBuilder struct {
+	client *client.Client
+}
+
+func (o *accessRequestBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return accessRequestResourceType
+}
+
+func newAccessRequestResource(request client.AccessRequest, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	description := fmt.Sprintf("%s asked to join %s", request.Member.Email, request.Team.Slug)
+	if request.Requester != nil {
+		description = fmt.Sprintf("%s asked for %s to join %s", request.Requester.Email, request.Member.Email, request.Team.Slug)
+	}
+
+	return resourceSdk.NewResource(
+		fmt.Sprintf("%s to %s", request.Member.Email, request.Team.Name),
+		accessRequestResourceType,
+		// <orgID>/<requestID>
+		fmt.Sprintf("%s/%s", parentResourceID.Resource, request.ID),
+		resourceSdk.WithParentResourceID(parentResourceID),
+		resourceSdk.WithDescription(description),
+	)
+}
+
+func (o *accessRequestBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, _ *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	requests, err := o.client.ListAccessRequests(ctx, parentResourceID.Resource)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	ret := make([]*v2.Resource, 0, len(requests))
+	for _, request := range requests {
+		resource, err := newAccessRequestResource(request, parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		ret = append(ret, resource)
+	}
+
+	return ret, "", nil, nil
+}
+
+// Get finds the request among the pending ones, Sentry has no endpoint to read a single access request.
+func (o *accessRequestBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	orgID, requestID, err := parseRequestID(accessRequestResourceType, resourceId.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	requests, err := o.client.ListAccessRequests(ctx, orgID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to list access requests: %w", err)
+	}
+	i := slices.IndexFunc(requests, func(request client.AccessRequest) bool { return request.ID == requestID })
+	if i < 0 {
+		return nil, nil, status.Errorf(codes.NotFound, "baton-sentry: access request %s is no longer pending", requestID)
+	}
+
+	resource, err := newAccessRequestResource(requests[i], &v2.ResourceId{
+		ResourceType: organizationResourceType.Id,
+		Resource:     orgID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource, nil, nil
+}
+
+func (o *accessRequestBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func (o *accessRequestBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newAccessRequestBuilder(client *client.Client) *accessRequestBuilder {
+	return &accessRequestBuilder{
+		client: client,
+	}
+}
+
+type inviteRequestBuilder struct {
+	client *client.Client
+}
+
+func (o *inviteRequestBuilder) ResourceType(_ context.Context) *v2.ResourceType {
+	return inviteRequestResourceType
+}
+
+func newInviteRequestResource(request client.InviteRequest, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+	description := fmt.Sprintf("%s asked to join as %s", request.Email, request.OrgRole)
+	if request.InviteStatus == "requested_to_be_invited" {
+		description = fmt.Sprintf("%s asked to invite %s as %s", request.InviterName, request.Email, request.OrgRole)
+	}
+	if len(request.Teams) > 0 {
+		description += fmt.Sprintf(" to %s", strings.Join(request.Teams, ", "))
+	}
+
+	return resourceSdk.NewResource(
+		request.Email,
+		inviteRequestResourceType,
+		// <orgID>/<memberID>
+		fmt.Sprintf("%s/%s", parentResourceID.Resource, request.ID),
+		resourceSdk.WithParentResourceID(parentResourceID),
+		resourceSdk.WithDescription(description),
+	)
+}
+
+func (o *inviteRequestBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId, pToken *pagination.Token) ([]*v2.Resource, string, annotations.Annotations, error) {
+	if parentResourceID == nil {
+		return nil, "", nil, nil
+	}
+
+	var cursor string
+	if pToken != nil {
+		cursor = pToken.Token
+	}
+
+	page, err := o.client.ListInviteRequests(ctx, parentResourceID.Resource, client.PageOptions{Cursor: cursor})
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	var annotations annotations.Annotations
+	annotations = *annotations.WithRateLimiting(page.RateLimit)
+
+	ret := make([]*v2.Resource, 0, len(page.Items))
+	for _, request := range page.Items {
+		resource, err := newInviteRequestResource(request, parentResourceID)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		ret = append(ret, resource)
+	}
+
+	return ret, page.NextCursor, annotations, nil
+}
+
+func (o *inviteRequestBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	orgID, memberID, err := parseRequestID(inviteRequestResourceType, resourceId.Resource)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	request, err := o.client.GetInviteRequest(ctx, orgID, memberID)
+	if client.IsNotFound(err) {
+		return nil, nil, status.Errorf(codes.NotFound, "baton-sentry: invite request %s is no longer pending", memberID)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to get invite request %s: %w", memberID, err)
+	}
+
+	resource, err := newInviteRequestResource(*request, &v2.ResourceId{
+		ResourceType: organizationResourceType.Id,
+		Resource:     orgID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource, nil, nil
+}
+
+func (o *inviteRequestBuilder) Entitlements(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func (o *inviteRequestBuilder) Grants(_ context.Context, _ *v2.Resource, _ *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
+	return nil, "", nil, nil
+}
+
+func newInviteRequestBuilder(client *client.Client) *inviteRequestBuilder {
+	return &inviteRequestBuilder{
+		client: client,
+	}
+}
+
+// parseRequestID parses the <orgID>/<requestID> ID of an access or invite request.
+func parseRequestID(resourceType *v2.ResourceType, id string) (string, string, error) {
+	orgID, requestID, ok := strings.Cut(id, "/")
+	if !ok || orgID == "" || requestID == "" {
+		return "", "", fmt.Errorf("baton-sentry: expected %s resource ID to be in the format 'orgId/requestId', got %s", resourceType.Id, id)
+	}
+	return orgID, requestID, nil
+}
+
+func reviewRequestSchema(name, displayName, description string, resourceType *v2.ResourceType) *v2.BatonActionSchema {
+	return &v2.BatonActionSchema{
+		Name:        name,
+		DisplayName: displayName,
+		Description: description,
+		Arguments: []*config.Field{
+			{
+				Name:        "request_id",
+				DisplayName: "Request ID",
+				Description: fmt.Sprintf("The ID of the %s resource, in the format 'orgId/requestId'.", resourceType.Id),
+				IsRequired:  true,
+				Field:       &config.Field_StringField{StringField: &config.StringField{}},
+			},
+		},
+		ReturnTypes: []*config.Field{
+			{
+				Name:        "success",
+				DisplayName: "Success",
+				Description: "Whether the request was reviewed.",
+				Field:       &config.Field_BoolField{BoolField: &config.BoolField{}},
+			},
+		},
+	}
+}
+
+// requestReviewer approves and denies the pending requests, so they go through the same review as any other
+// access instead of whichever team admin or manager sees them first in Sentry.
+type requestReviewer struct {
+	client *client.Client
+}
+
+func (r *requestReviewer) register(ctx context.Context, m *actions.ActionManager) error {
+	reviews := []struct {
+		schema  *v2.BatonActionSchema
+		handler actions.ActionHandler
+	}{
+		{
+			reviewRequestSchema(approveAccessRequestAction, "Approve team join request",
+				"Adds the member to the team they asked to join.", accessRequestResourceType),
+			r.review(func(ctx context.Context, orgID, requestID string) error {
+				return r.client.ReviewAccessRequest(ctx, orgID, requestID, true)
+			}),
+		},
+		{
+			reviewRequestSchema(denyAccessRequestAction, "Deny team join request",
+				"Denies a request to join a team.", accessRequestResourceType),
+			r.review(func(ctx context.Context, orgID, requestID string) error {
+				return r.client.ReviewAccessRequest(ctx, orgID, requestID, false)
+			}),
+		},
+		{
+			reviewRequestSchema(approveInviteRequestAction, "Approve invite request",
+				"Sends the requested invite to join the organization.", inviteRequestResourceType),
+			r.review(r.client.ApproveInviteRequest),
+		},
+		{
+			reviewRequestSchema(denyInviteRequestAction, "Deny invite request",
+				"Denies a request to invite someone to the organization.", inviteRequestResourceType),
+			r.review(r.client.DenyInviteRequest),
+		},
+	}
+
+	for _, review := range reviews {
+		if err := m.RegisterAction(ctx, review.schema.Name, review.schema, review.handler); err != nil {
+			return fmt.Errorf("baton-sentry: failed to register %s action: %w", review.schema.Name, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *requestReviewer) review(send func(ctx context.Context, orgID, requestID string) error) actions.ActionHandler {
+	return func(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+		ctx, simulations := client.WithSimulations(ctx)
+
+		orgID, requestID, ok := strings.Cut(stringArg(args, "request_id"), "/")
+		if !ok || orgID == "" || requestID == "" {
+			return nil, nil, status.Error(codes.InvalidArgument, "baton-sentry: request_id must be in the format 'orgId/requestId'")
+		}
+
+		err := send(ctx, orgID, requestID)
+		if client.IsNotFound(err) {
+			return nil, nil, status.Errorf(codes.NotFound, "baton-sentry: request %s is no longer pending", requestID)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-sentry: failed to review request %s: %w", requestID, err)
+		}
+
+		rv := &structpb.Struct{Fields: map[string]*structpb.Value{
+			"success": structpb.NewBoolValue(true),
+		}}
+		return rv, simulatedAnnotations(simulations), nil
+	}
+}
@@ -28,3 +28,45 @@ var projectResourceType = &v2.ResourceType{
 	DisplayName: "Project",
 	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_GROUP},
 }
+
+// Metric alert rules belong to an organization and issue alert rules to a project.
+var alertRuleResourceType = &v2.ResourceType{
+	Id:          "alert_rule",
+	DisplayName: "Alert Rule",
+}
+
+// Cron and uptime monitors both belong to an organization.
+var monitorResourceType = &v2.ResourceType{
+	Id:          "monitor",
+	DisplayName: "Monitor",
+}
+
+// Service hooks and enabled plugins of a project, both send event data out of Sentry.
+var dataForwarderResourceType = &v2.ResourceType{
+	Id:          "data_forwarder",
+	DisplayName: "Data Forwarder",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_APP},
+}
+
+var dashboardResourceType = &v2.ResourceType{
+	Id:          "dashboard",
+	DisplayName: "Dashboard",
+}
+
+// Pending team join and invite requests, reviewed with the approve and deny actions.
+var accessRequestResourceType = &v2.ResourceType{
+	Id:          "access_request",
+	DisplayName: "Team Join Request",
+}
+
+var inviteRequestResourceType = &v2.ResourceType{
+	Id:          "invite_request",
+	DisplayName: "Invite Request",
+}
+
+// Relays trusted to forward events on behalf of an organization, identified by their public key.
+var relayResourceType = &v2.ResourceType{
+	Id:          "relay",
+	DisplayName: "Trusted Relay",
+	Traits:      []v2.ResourceType_Trait{v2.ResourceType_TRAIT_SECRET},
+}
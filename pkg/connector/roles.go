@@ -0,0 +1,315 @@
+package connector
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	config "github.com/conductorone/baton-sdk/pb/c1/config/v1"
+	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
+	"github.com/conductorone/baton-sdk/pkg/annotations"
+	"github.com/conductorone/baton-sdk/pkg/types/entitlement"
+	"github.com/conductorone/baton-sdk/pkg/types/grant"
+	"github.com/conductorone/baton-sentry/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// Organization roles and the scopes they give are entitlements of the organization. Role slugs are prefixed so
+// the member role doesn't collide with the membership entitlement.
+const (
+	orgRolePrefix = "role:"
+	scopePrefix   = "scope:"
+)
+
+func orgRoleSlug(roleID string) string {
+	return orgRolePrefix + roleID
+}
+
+func scopeSlug(scope string) string {
+	return scopePrefix + scope
+}
+
+func orgRoleEntitlementID(orgID, roleID string) string {
+	return fmt.Sprintf("organization:%s:%s", orgID, orgRoleSlug(roleID))
+}
+
+// entitlementOrgRole returns the role of an organization role entitlement, grants only carry the ID of their
+// entitlement.
+func entitlementOrgRole(e *v2.Entitlement) (string, bool) {
+	_, roleID, ok := strings.Cut(e.Id, ":"+orgRolePrefix)
+	return roleID, ok
+}
+
+// orgRoleEntitlements returns an entitlement for each organization role. Granting a role changes the member's role,
+// revoking it moves them back to the member role. Retired roles can't be requested, members still holding one are
+// moved to a current role with the migrate_retired_role action.
+func orgRoleEntitlements(resource *v2.Resource, roles []client.OrganizationRole) []*v2.Entitlement {
+	ret := make([]*v2.Entitlement, 0, len(roles))
+	for _, role := range roles {
+		options := []entitlement.EntitlementOption{
+			entitlement.WithDescription(role.Desc),
+			entitlement.WithDisplayName(fmt.Sprintf("%s of %s organization", role.Name, resource.DisplayName)),
+			entitlement.WithGrantableTo(userResourceType),
+		}
+		if role.IsRetired {
+			options = []entitlement.EntitlementOption{
+				entitlement.WithDescription(fmt.Sprintf("Retired role, it can no longer be assigned. %s", role.Desc)),
+				entitlement.WithDisplayName(fmt.Sprintf("%s (retired) of %s organization", role.Name, resource.DisplayName)),
+				entitlement.WithGrantableTo(userResourceType),
+				entitlement.WithAnnotation(&v2.EntitlementImmutable{}),
+			}
+		}
+
+		ret = append(ret, entitlement.NewPermissionEntitlement(resource, orgRoleSlug(role.ID), options...))
+	}
+
+	return ret
+}
+
+// retiredOrgRoles returns the retired roles of the organization by ID.
+func retiredOrgRoles(ctx context.Context, c *client.Client, orgID string) (map[string]bool, error) {
+	roles, err := c.GetOrganizationRoles(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get roles of organization %s: %w", orgID, err)
+	}
+
+	return client.RetiredOrgRoles(roles.OrgRoles), nil
+}
+
+// rolesByScope returns the IDs of the roles giving each scope, the scopes sorted. roleScopes returns the ID of a role
+// and its scopes.
+func rolesByScope[R any](roles []R, roleScopes func(R) (string, []string)) ([]string, map[string][]string) {
+	byScope := make(map[string][]string)
+	for _, role := range roles {
+		id, scopes := roleScopes(role)
+		for _, scope := range scopes {
+			byScope[scope] = append(byScope[scope], id)
+		}
+	}
+
+	scopes := make([]string, 0, len(byScope))
+	for scope := range byScope {
+		scopes = append(scopes, scope)
+	}
+	slices.Sort(scopes)
+
+	return scopes, byScope
+}
+
+func orgRolesByScope(roles []client.OrganizationRole) ([]string, map[string][]string) {
+	return rolesByScope(roles, func(role client.OrganizationRole) (string, []string) { return role.ID, role.Scopes })
+}
+
+func teamRolesByScope(roles []client.TeamRole) ([]string, map[string][]string) {
+	return rolesByScope(roles, func(role client.TeamRole) (string, []string) { return role.ID, role.Scopes })
+}
+
+// teamRoleEntitlementID returns the team entitlement held by the members with a team role. Every member of a team
+// holds at least the minimum team role, admins also hold the admin entitlement.
+func teamRoleEntitlementID(orgID, teamID, roleID string) string {
+	if roleID == client.TeamRoleAdmin {
+		return fmt.Sprintf("team:%s/%s:%s", orgID, teamID, teamAdmin)
+	}
+	return fmt.Sprintf("team:%s/%s:%s", orgID, teamID, teamMembership)
+}
+
+// scopeEntitlements returns an entitlement for each scope given by an organization role. They can't be granted,
+// members get them through their role. Scopes of team roles are entitlements of each team, see teamScopeEntitlements.
+func scopeEntitlements(resource *v2.Resource, org *client.Organization) []*v2.Entitlement {
+	scopes, _ := orgRolesByScope(org.OrgRoleList)
+
+	ret := make([]*v2.Entitlement, 0, len(scopes))
+	for _, scope := range scopes {
+		ret = append(ret, entitlement.NewPermissionEntitlement(
+			resource,
+			scopeSlug(scope),
+			entitlement.WithDescription(fmt.Sprintf("Members whose organization role has the %s scope in %s organization", scope, resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s scope in %s organization", scope, resource.DisplayName)),
+			entitlement.WithGrantableTo(userResourceType, organizationResourceType),
+			entitlement.WithAnnotation(&v2.EntitlementImmutable{}),
+		))
+	}
+
+	return ret
+}
+
+// scopeGrants grants each scope to the organization itself, expanded to the members of every organization role that
+// has it.
+func scopeGrants(resource *v2.Resource, org *client.Organization) []*v2.Grant {
+	orgID := resource.Id.Resource
+	scopes, byScope := orgRolesByScope(org.OrgRoleList)
+
+	ret := make([]*v2.Grant, 0, len(scopes))
+	for _, scope := range scopes {
+		entitlementIDs := make([]string, 0, len(byScope[scope]))
+		for _, roleID := range byScope[scope] {
+			entitlementIDs = append(entitlementIDs, orgRoleEntitlementID(orgID, roleID))
+		}
+
+		ret = append(ret, grant.NewGrant(
+			resource,
+			scopeSlug(scope),
+			resource.Id,
+			grant.WithAnnotation(
+				&v2.GrantExpandable{EntitlementIds: entitlementIDs, Shallow: true},
+				&v2.GrantImmutable{},
+			),
+		))
+	}
+
+	return ret
+}
+
+// teamScopeEntitlements returns an entitlement for each scope given by a team role. The scopes only apply to the
+// projects of the team.
+func teamScopeEntitlements(resource *v2.Resource, teamRoles []client.TeamRole) []*v2.Entitlement {
+	scopes, _ := teamRolesByScope(teamRoles)
+
+	ret := make([]*v2.Entitlement, 0, len(scopes))
+	for _, scope := range scopes {
+		ret = append(ret, entitlement.NewPermissionEntitlement(
+			resource,
+			scopeSlug(scope),
+			entitlement.WithDescription(fmt.Sprintf("Members whose role on %s team has the %s scope, on the projects of the team", resource.DisplayName, scope)),
+			entitlement.WithDisplayName(fmt.Sprintf("%s scope on %s team projects", scope, resource.DisplayName)),
+			entitlement.WithGrantableTo(userResourceType, teamResourceType),
+			entitlement.WithAnnotation(&v2.EntitlementImmutable{}),
+		))
+	}
+
+	return ret
+}
+
+// teamScopeGrants grants each scope of the team roles to the team itself, expanded to the members holding a role
+// that has it on the team.
+func teamScopeGrants(resource *v2.Resource, teamRoles []client.TeamRole) []*v2.Grant {
+	orgID := resource.ParentResourceId.Resource
+	teamID := strings.Split(resource.Id.Resource, "/")[1]
+	scopes, byScope := teamRolesByScope(teamRoles)
+
+	ret := make([]*v2.Grant, 0, len(scopes))
+	for _, scope := range scopes {
+		entitlementIDs := make([]string, 0, len(byScope[scope]))
+		for _, roleID := range byScope[scope] {
+			entitlementIDs = append(entitlementIDs, teamRoleEntitlementID(orgID, teamID, roleID))
+		}
+
+		ret = append(ret, grant.NewGrant(
+			resource,
+			scopeSlug(scope),
+			resource.Id,
+			grant.WithAnnotation(
+				&v2.GrantExpandable{EntitlementIds: entitlementIDs, Shallow: true},
+				&v2.GrantImmutable{},
+			),
+		))
+	}
+
+	return ret
+}
+
+const migrateRetiredRoleAction = "migrate_retired_role"
+
+var migrateRetiredRoleSchema = &v2.BatonActionSchema{
+	Name:        migrateRetiredRoleAction,
+	DisplayName: "Migrate retired role",
+	Description: "Moves a member holding a retired organization role, such as the legacy admin role, to a current role.",
+	Arguments: []*config.Field{
+		{
+			Name:        "member_id",
+			DisplayName: "Member ID",
+			Description: "The ID of the organization member holding a retired role.",
+			IsRequired:  true,
+			Field:       &config.Field_StringField{StringField: &config.StringField{}},
+		},
+		{
+			Name:        "role",
+			DisplayName: "Role",
+			Description: "The ID of the current organization role to move the member to, e.g. member or manager.",
+			IsRequired:  true,
+			Field:       &config.Field_StringField{StringField: &config.StringField{}},
+		},
+		{
+			Name:        "org_id",
+			DisplayName: "Organization ID",
+			Description: "The organization of the member, looked up from the member ID when empty.",
+			Field:       &config.Field_StringField{StringField: &config.StringField{}},
+		},
+	},
+	ReturnTypes: []*config.Field{
+		{
+			Name:        "previous_role",
+			DisplayName: "Previous role",
+			Description: "The retired role the member held.",
+			Field:       &config.Field_StringField{StringField: &config.StringField{}},
+		},
+		{
+			Name:        "success",
+			DisplayName: "Success",
+			Description: "Whether the member was moved to the new role.",
+			Field:       &config.Field_BoolField{BoolField: &config.BoolField{}},
+		},
+	},
+}
+
+type roleMigrator struct {
+	client *client.Client
+}
+
+// migrate changes the role of a member holding a retired role. Members holding a current role are left alone, role
+// changes in general go through the role entitlements.
+func (r *roleMigrator) migrate(ctx context.Context, args *structpb.Struct) (*structpb.Struct, annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
+	memberID := stringArg(args, "member_id")
+	if memberID == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "baton-sentry: member_id is required")
+	}
+	roleID := stringArg(args, "role")
+	if roleID == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "baton-sentry: role is required")
+	}
+
+	orgID := stringArg(args, "org_id")
+	if orgID == "" {
+		var err error
+		orgID, err = client.FindUserOrgID(ctx, r.client, memberID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-sentry: failed to find organization for user %s: %w", memberID, err)
+		}
+	}
+
+	member, _, err := r.client.GetOrganizationMember(ctx, orgID, memberID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to get organization member %s: %w", memberID, err)
+	}
+
+	i := slices.IndexFunc(member.OrgRoleList, func(role client.OrganizationRole) bool { return role.ID == roleID })
+	if i < 0 || member.OrgRoleList[i].IsRetired {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "baton-sentry: %s is not a current role of organization %s", roleID, orgID)
+	}
+	if !client.RetiredOrgRoles(member.OrgRoleList)[member.OrgRole] {
+		return nil, nil, status.Errorf(codes.FailedPrecondition,
+			"baton-sentry: member %s holds the %s role, which is not retired", memberID, member.OrgRole)
+	}
+	if member.Flags.IDPRoleRestricted {
+		return nil, nil, managedByIDPError("member role", memberID)
+	}
+
+	err = r.client.UpdateMemberOrgRole(ctx, orgID, memberID, roleID)
+	if client.IsManagedByIdentityProvider(err) {
+		return nil, nil, managedByIDPError("member role", memberID)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to change role of member %s to %s: %w", memberID, roleID, err)
+	}
+
+	rv := &structpb.Struct{Fields: map[string]*structpb.Value{
+		"previous_role": structpb.NewStringValue(member.OrgRole),
+		"success":       structpb.NewBoolValue(true),
+	}}
+	return rv, simulatedAnnotations(simulations), nil
+}
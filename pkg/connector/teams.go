@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
@@ -12,9 +13,22 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/types/grant"
 	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/conductorone/baton-sentry/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
-const teamMembership = "member"
+const (
+	teamMembership = "member"
+	teamAdmin      = "admin"
+	// teamCanJoin is held by the members who may join the team without approval, it is not a membership.
+	teamCanJoin = "can_join"
+)
+
+// isTeamMembershipEntitlement tells the membership apart by its ID, it is the only team entitlement that can be
+// granted or revoked.
+func isTeamMembershipEntitlement(e *v2.Entitlement) bool {
+	return strings.HasSuffix(e.Id, ":"+teamMembership)
+}
 
 type teamBuilder struct {
 	client *client.Client
@@ -26,16 +40,24 @@ func (o *teamBuilder) ResourceType(ctx context.Context) *v2.ResourceType {
 
 func newTeamResource(team client.Team, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
 	profile := map[string]interface{}{
-		"org_id": parentResourceID.Resource,
+		"org_id":    parentResourceID.Resource,
+		"team_slug": team.Slug,
+		// The members of teams provisioned through SCIM are managed by the identity provider.
+		"idp_provisioned": team.Flags.IDPProvisioned,
 	}
+	groupTraitOptions := []resourceSdk.GroupTraitOption{
+		resourceSdk.WithGroupProfile(profile),
+	}
+	if icon := avatarAssetRef(client.TeamAvatar, parentResourceID.Resource, team.Avatar); icon != nil {
+		groupTraitOptions = append(groupTraitOptions, resourceSdk.WithGroupIcon(icon))
+	}
+
 	return resourceSdk.NewGroupResource(
 		team.Name,
 		teamResourceType,
 		// <orgID>/<teamID>
 		fmt.Sprintf("%s/%s", parentResourceID.Resource, team.ID),
-		[]resourceSdk.GroupTraitOption{
-			resourceSdk.WithGroupProfile(profile),
-		},
+		groupTraitOptions,
 		resourceSdk.WithParentResourceID(parentResourceID),
 	)
 }
@@ -51,16 +73,16 @@ func (o *teamBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 	}
 
 	orgID := parentResourceID.Resource
-	teams, res, ratelimitDescription, err := o.client.ListTeams(ctx, orgID, cursor)
+	page, err := o.client.ListTeams(ctx, orgID, client.PageOptions{Cursor: cursor})
 	if err != nil {
 		return nil, "", nil, err
 	}
 
 	var annotations annotations.Annotations
-	annotations = *annotations.WithRateLimiting(ratelimitDescription)
+	annotations = *annotations.WithRateLimiting(page.RateLimit)
 
-	ret := make([]*v2.Resource, 0, len(teams))
-	for _, team := range teams {
+	ret := make([]*v2.Resource, 0, len(page.Items))
+	for _, team := range page.Items {
 		resource, err := newTeamResource(team, parentResourceID)
 		if err != nil {
 			return nil, "", nil, err
@@ -69,24 +91,74 @@ func (o *teamBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 		ret = append(ret, resource)
 	}
 
-	var nextCursor string
-	if client.HasNextPage(res) {
-		nextCursor = client.NextCursor(res)
+	return ret, page.NextCursor, annotations, nil
+}
+
+func (o *teamBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, _ *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	split := strings.Split(resourceId.Resource, "/")
+	if len(split) != 2 {
+		return nil, nil, fmt.Errorf("baton-sentry: expected team resource ID to be in the format 'orgId/teamId', got %s", resourceId.Resource)
+	}
+
+	orgID := split[0]
+	teamID := split[1]
+	team, _, err := o.client.GetTeam(ctx, orgID, teamID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to get team %s: %w", teamID, err)
 	}
 
-	return ret, nextCursor, annotations, nil
+	resource, err := newTeamResource(*team, &v2.ResourceId{
+		ResourceType: organizationResourceType.Id,
+		Resource:     orgID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource, nil, nil
 }
 
-func (o *teamBuilder) Entitlements(_ context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
-	return []*v2.Entitlement{
-		entitlement.NewAssignmentEntitlement(
+// Entitlements returns the membership of the team, its admin role, the ability to join it and the scopes the team
+// roles give on its projects. The admin role can also be held through an organization role with admin as minimum team
+// role, and the organization's open membership lets every active member join.
+func (o *teamBuilder) Entitlements(ctx context.Context, resource *v2.Resource, _ *pagination.Token) ([]*v2.Entitlement, string, annotations.Annotations, error) {
+	orgID := resource.ParentResourceId.Resource
+	roles, err := o.client.GetOrganizationRoles(ctx, orgID)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-sentry: failed to get roles of organization %s: %w", orgID, err)
+	}
+
+	options := []entitlement.EntitlementOption{
+		entitlement.WithDescription(fmt.Sprintf("Member of %s team", resource.DisplayName)),
+		entitlement.WithDisplayName(fmt.Sprintf("Member of %s team", resource.DisplayName)),
+		entitlement.WithGrantableTo(userResourceType),
+	}
+	if isManagedByIDP(ctx, o.client, orgID, isIDPProvisioned(resource)) {
+		options = append(options, entitlement.WithAnnotation(&v2.EntitlementImmutable{}))
+	}
+
+	ret := []*v2.Entitlement{
+		entitlement.NewAssignmentEntitlement(resource, teamMembership, options...),
+		entitlement.NewPermissionEntitlement(
+			resource,
+			teamAdmin,
+			entitlement.WithDescription(fmt.Sprintf("Can manage the members and projects of %s team", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("Team Admin of %s team", resource.DisplayName)),
+			entitlement.WithGrantableTo(userResourceType, organizationResourceType),
+			entitlement.WithAnnotation(&v2.EntitlementImmutable{}),
+		),
+		entitlement.NewPermissionEntitlement(
 			resource,
-			teamMembership,
-			entitlement.WithDescription(fmt.Sprintf("Member of %s team", resource.DisplayName)),
-			entitlement.WithDisplayName(fmt.Sprintf("Member of %s team", resource.DisplayName)),
-			entitlement.WithGrantableTo(userResourceType),
+			teamCanJoin,
+			entitlement.WithDescription(fmt.Sprintf("Can join %s team without approval", resource.DisplayName)),
+			entitlement.WithDisplayName(fmt.Sprintf("Can join %s team", resource.DisplayName)),
+			entitlement.WithGrantableTo(userResourceType, organizationResourceType),
+			entitlement.WithAnnotation(&v2.EntitlementImmutable{}),
 		),
-	}, "", nil, nil
+	}
+	ret = append(ret, teamScopeEntitlements(resource, roles.TeamRoles)...)
+
+	return ret, "", nil, nil
 }
 
 func (o *teamBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken *pagination.Token) ([]*v2.Grant, string, annotations.Annotations, error) {
@@ -97,60 +169,160 @@ func (o *teamBuilder) Grants(ctx context.Context, resource *v2.Resource, pToken
 
 	orgID := resource.ParentResourceId.Resource
 	teamID := strings.Split(resource.Id.Resource, "/")[1]
-	members, res, ratelimitDescription, err := o.client.ListTeamMembers(ctx, orgID, teamID, cursor)
+	page, err := o.client.ListTeamMembers(ctx, orgID, teamID, client.PageOptions{Cursor: cursor})
 	if err != nil {
 		return nil, "", nil, err
 	}
 
 	var annotations annotations.Annotations
-	annotations = *annotations.WithRateLimiting(ratelimitDescription)
+	annotations = *annotations.WithRateLimiting(page.RateLimit)
+
+	var grantOptions []grant.GrantOption
+	if isManagedByIDP(ctx, o.client, orgID, isIDPProvisioned(resource)) {
+		grantOptions = append(grantOptions, grant.WithAnnotation(&v2.GrantImmutable{}))
+	}
+
+	var ret []*v2.Grant
+	// The implicit and scope grants don't depend on the members, they come with the first page. Fetching the
+	// organization for them also refreshes its roles, which the next pages reuse.
+	if cursor == "" {
+		org, _, err := o.client.GetOrganization(ctx, orgID)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("baton-sentry: failed to get organization %s: %w", orgID, err)
+		}
+		implicit, err := implicitGrants(resource, org)
+		if err != nil {
+			return nil, "", nil, err
+		}
+		ret = append(ret, implicit...)
+		ret = append(ret, teamScopeGrants(resource, org.TeamRoleList)...)
+	}
 
-	ret := make([]*v2.Grant, 0, len(members))
-	for _, member := range members {
+	roles, err := o.client.GetOrganizationRoles(ctx, orgID)
+	if err != nil {
+		return nil, "", nil, fmt.Errorf("baton-sentry: failed to get roles of organization %s: %w", orgID, err)
+	}
+
+	for _, member := range page.Items {
 		resourceId, err := resourceSdk.NewResourceID(userResourceType, member.ID)
 		if err != nil {
 			return nil, "", nil, fmt.Errorf("baton-sentry: failed to create resource ID for user %s: %w", member.ID, err)
 		}
 
-		ret = append(ret, grant.NewGrant(resource, teamMembership, resourceId))
+		ret = append(ret, grant.NewGrant(resource, teamMembership, resourceId, grantOptions...))
+		if memberTeamRole(member, roles.OrgRoles) == client.TeamRoleAdmin {
+			ret = append(ret, grant.NewGrant(resource, teamAdmin, resourceId, grant.WithAnnotation(&v2.GrantImmutable{})))
+		}
+	}
+
+	return ret, page.NextCursor, annotations, nil
+}
+
+// implicitGrants grants the team to its organization, expanded to the members holding the access without being
+// granted it on the team. Members whose organization role allows team roles and has admin as minimum team role are
+// admin of every team, and with open membership every active member can join any team, which doesn't make them
+// members. Roles that don't allow team roles, like the retired admin role, only give their minimum team role on the
+// teams of the member.
+func implicitGrants(resource *v2.Resource, org *client.Organization) ([]*v2.Grant, error) {
+	orgResourceID, err := resourceSdk.NewResourceID(organizationResourceType, org.ID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to create resource ID for organization %s: %w", org.ID, err)
+	}
+
+	var ret []*v2.Grant
+	var adminRoles []string
+	// Invites are members of the organization too, only the role entitlements are limited to active members.
+	activeRoles := make([]string, 0, len(org.OrgRoleList))
+	for _, role := range org.OrgRoleList {
+		activeRoles = append(activeRoles, orgRoleEntitlementID(org.ID, role.ID))
+		if role.IsTeamRolesAllowed && role.MinimumTeamRole == client.TeamRoleAdmin {
+			adminRoles = append(adminRoles, orgRoleEntitlementID(org.ID, role.ID))
+		}
+	}
+	if len(adminRoles) > 0 {
+		ret = append(ret, grant.NewGrant(
+			resource,
+			teamAdmin,
+			orgResourceID,
+			grant.WithAnnotation(
+				&v2.GrantExpandable{EntitlementIds: adminRoles, Shallow: true},
+				&v2.GrantImmutable{},
+			),
+		))
 	}
 
-	var nextCursor string
-	if client.HasNextPage(res) {
-		nextCursor = client.NextCursor(res)
+	if org.OpenMembership {
+		ret = append(ret, grant.NewGrant(
+			resource,
+			teamCanJoin,
+			orgResourceID,
+			grant.WithAnnotation(
+				&v2.GrantExpandable{EntitlementIds: activeRoles, Shallow: true},
+				&v2.GrantImmutable{},
+			),
+		))
 	}
 
-	return ret, nextCursor, annotations, nil
+	return ret, nil
+}
+
+// memberTeamRole returns the team role a member holds on the team. Members whose organization role doesn't allow
+// team roles hold the minimum team role of their organization role instead of the one reported for the team.
+func memberTeamRole(member client.TeamMember, roles []client.OrganizationRole) string {
+	i := slices.IndexFunc(roles, func(role client.OrganizationRole) bool { return role.ID == member.OrgRole })
+	if i >= 0 && !roles[i].IsTeamRolesAllowed {
+		return roles[i].MinimumTeamRole
+	}
+	return member.TeamRole
 }
 
 func (o *teamBuilder) Grant(ctx context.Context, principal *v2.Resource, entitlement *v2.Entitlement) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
 	split := strings.Split(entitlement.Resource.Id.Resource, "/")
 
 	orgId := split[0]
 	teamId := split[1]
 	memberId := principal.Id.Resource
-	teamName := entitlement.Resource.DisplayName
 
-	member, _, err := o.client.GetOrganizationMember(ctx, orgId, memberId)
+	if principal.Id.ResourceType != userResourceType.Id || !isTeamMembershipEntitlement(entitlement) {
+		return nil, fmt.Errorf("baton-sentry: only team membership can be granted, and only to users")
+	}
+
+	teamSlug, err := o.teamSlug(ctx, orgId, teamId)
+	if err != nil {
+		return nil, err
+	}
+
+	member, _, err := o.client.GetOrganizationMember(client.WithoutCache(ctx), orgId, memberId)
 	if err != nil {
 		return nil, fmt.Errorf("baton-sentry: failed to get organization member: %w", err)
 	}
 
-	for _, name := range member.Teams {
-		if name == teamName {
-			return annotations.New(&v2.GrantAlreadyExists{}), nil
-		}
+	if isTeamMember(member, teamSlug) {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
+	if isManagedByIDP(ctx, o.client, orgId, isIDPProvisioned(entitlement.Resource)) {
+		return nil, managedByIDPError("team", teamSlug)
 	}
 
-	_, err = o.client.AddOrgMemberToTeam(ctx, orgId, memberId, teamId)
+	err = addTeamMember(ctx, o.client, orgId, teamId, memberId)
+	if client.IsManagedByIdentityProvider(err) {
+		return nil, managedByIDPError("team", teamSlug)
+	}
+	if client.IsConflict(err) {
+		return annotations.New(&v2.GrantAlreadyExists{}), nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("baton-sentry: failed to add organization member to team: %w", err)
 	}
 
-	return nil, nil
+	return simulatedAnnotations(simulations), nil
 }
 
 func (o *teamBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
 	entitlement := grant.Entitlement
 	split := strings.Split(entitlement.Resource.Id.Resource, "/")
 
@@ -158,31 +330,77 @@ func (o *teamBuilder) Revoke(ctx context.Context, grant *v2.Grant) (annotations.
 	teamId := split[1]
 
 	memberId := grant.Principal.Id.Resource
-	teamName := entitlement.Resource.DisplayName
 
-	member, _, err := o.client.GetOrganizationMember(ctx, orgId, memberId)
+	// Implicit access follows from the organization's settings and roles, it can't be revoked on the team.
+	if grant.Principal.Id.ResourceType != userResourceType.Id || !isTeamMembershipEntitlement(entitlement) {
+		return nil, status.Errorf(codes.FailedPrecondition,
+			"baton-sentry: %s can't be revoked on team %s, it follows from the organization's roles or settings", grant.Id, teamId)
+	}
+
+	teamSlug, err := o.teamSlug(ctx, orgId, teamId)
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
 	if err != nil {
-		return nil, fmt.Errorf("baton-sentry: failed to get organization member: %w", err)
+		return nil, err
 	}
 
-	exists := false
-	for _, name := range member.Teams {
-		if name == teamName {
-			exists = true
-			break
-		}
+	member, _, err := o.client.GetOrganizationMember(client.WithoutCache(ctx), orgId, memberId)
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get organization member: %w", err)
 	}
 
-	if !exists {
+	if !isTeamMember(member, teamSlug) {
 		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
 	}
+	if isManagedByIDP(ctx, o.client, orgId, isIDPProvisioned(entitlement.Resource)) {
+		return nil, managedByIDPError("team", teamSlug)
+	}
 
-	_, err = o.client.DeleteOrgMemberFromTeam(ctx, orgId, memberId, teamId)
+	err = removeTeamMember(ctx, o.client, orgId, teamId, memberId)
+	if client.IsManagedByIdentityProvider(err) {
+		return nil, managedByIDPError("team", teamSlug)
+	}
+	if client.IsNotFound(err) {
+		return annotations.New(&v2.GrantAlreadyRevoked{}), nil
+	}
 	if err != nil {
 		return nil, fmt.Errorf("baton-sentry: failed to delete organization member from team: %w", err)
 	}
 
-	return nil, nil
+	return simulatedAnnotations(simulations), nil
+}
+
+// teamSlug returns the current slug of a team. The slug saved on the resource is not used, it is outdated as soon as
+// the team is renamed and memberships are only reported by slug.
+func (o *teamBuilder) teamSlug(ctx context.Context, orgID, teamID string) (string, error) {
+	team, _, err := o.client.GetTeam(client.WithoutCache(ctx), orgID, teamID)
+	if err != nil {
+		return "", fmt.Errorf("baton-sentry: failed to get team %s: %w", teamID, err)
+	}
+
+	return team.Slug, nil
+}
+
+// isTeamMember reports whether the member belongs to the team with the given slug.
+// Sentry reports memberships by slug, never by display name.
+func isTeamMember(member *client.DetailedMember, teamSlug string) bool {
+	for _, teamRole := range member.TeamRoles {
+		if teamRole.TeamSlug == teamSlug {
+			return true
+		}
+	}
+
+	for _, slug := range member.Teams {
+		if slug == teamSlug {
+			return true
+		}
+	}
+
+	return false
 }
 
 func newTeamBuilder(client *client.Client) *teamBuilder {
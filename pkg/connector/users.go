@@ -3,6 +3,7 @@ package connector
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	v2 "github.com/conductorone/baton-sdk/pb/c1/connector/v2"
 	"github.com/conductorone/baton-sdk/pkg/annotations"
@@ -10,32 +11,54 @@ import (
 	"github.com/conductorone/baton-sdk/pkg/pagination"
 	resourceSdk "github.com/conductorone/baton-sdk/pkg/types/resource"
 	"github.com/conductorone/baton-sentry/pkg/client"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type userBuilder struct {
 	client *client.Client
+
+	mtx sync.Mutex
+	// owners holds the number of active owners of each organization, counted once per listing of its members.
+	owners map[string]int
 }
 
 func (o *userBuilder) ResourceType(_ context.Context) *v2.ResourceType {
 	return userResourceType
 }
 
-func newUserResource(member client.OrganizationMember, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
+// newUserResource builds the resource of a member. hasRetiredRole flags members whose organization role is retired,
+// they keep it until they are migrated to a current role.
+func newUserResource(member client.OrganizationMember, isOnlyOwner, hasRetiredRole bool, parentResourceID *v2.ResourceId) (*v2.Resource, error) {
 	profile := map[string]interface{}{
-		"expired":       member.Expired,
-		"invite_status": member.InviteStatus,
-		"org_id":        parentResourceID.Resource,
+		"expired":          member.Expired,
+		"invite_status":    member.InviteStatus,
+		"org_id":           parentResourceID.Resource,
+		"is_only_owner":    isOnlyOwner,
+		"org_role":         member.OrgRole,
+		"has_retired_role": hasRetiredRole,
+		// Members provisioned through SCIM can't be removed, and role restricted ones can't change role, outside
+		// of the identity provider.
+		"idp_provisioned":     member.Flags.IDPProvisioned,
+		"idp_role_restricted": member.Flags.IDPRoleRestricted,
+	}
+
+	userTraitOptions := []resourceSdk.UserTraitOption{
+		resourceSdk.WithEmail(member.Email, true),
+		resourceSdk.WithUserProfile(profile),
+		resourceSdk.WithCreatedAt(member.DateCreated),
+	}
+	if member.User != nil {
+		if icon := avatarAssetRef(client.UserAvatar, parentResourceID.Resource, member.User.Avatar); icon != nil {
+			userTraitOptions = append(userTraitOptions, resourceSdk.WithUserIcon(icon))
+		}
 	}
 
 	return resourceSdk.NewUserResource(
 		member.Name,
 		userResourceType,
 		member.ID,
-		[]resourceSdk.UserTraitOption{
-			resourceSdk.WithEmail(member.Email, true),
-			resourceSdk.WithUserProfile(profile),
-			resourceSdk.WithCreatedAt(member.DateCreated),
-		},
+		userTraitOptions,
 		resourceSdk.WithParentResourceID(parentResourceID),
 	)
 }
@@ -52,28 +75,102 @@ func (o *userBuilder) List(ctx context.Context, parentResourceID *v2.ResourceId,
 		cursor = pToken.Token
 	}
 
-	members, res, ratelimitDescription, err := o.client.ListOrganizationMembers(ctx, parentResourceID.Resource, cursor)
+	page, err := o.client.ListOrganizationMembers(ctx, parentResourceID.Resource, client.PageOptions{Cursor: cursor})
 	if err != nil {
 		return nil, "", nil, err
 	}
 	var annotations annotations.Annotations
-	annotations = *annotations.WithRateLimiting(ratelimitDescription)
+	annotations = *annotations.WithRateLimiting(page.RateLimit)
+
+	var retired map[string]bool
+	if len(page.Items) > 0 {
+		retired, err = retiredOrgRoles(ctx, o.client, parentResourceID.Resource)
+		if err != nil {
+			return nil, "", nil, err
+		}
+	}
+
+	// The members list doesn't say whether a member is the only owner, the owners are only counted when
+	// a page has one, once per listing. A new listing counts them again.
+	if cursor == "" {
+		o.forgetOwners(parentResourceID.Resource)
+	}
+	owners := -1
+	ret := make([]*v2.Resource, 0, len(page.Items))
+	for _, member := range page.Items {
+		if client.IsActiveOwner(member) && owners < 0 {
+			owners, err = o.ownerCount(ctx, parentResourceID.Resource)
+			if err != nil {
+				return nil, "", nil, err
+			}
+		}
 
-	ret := make([]*v2.Resource, 0, len(members))
-	for _, member := range members {
-		resource, err := newUserResource(member, parentResourceID)
+		resource, err := newUserResource(member, client.IsActiveOwner(member) && owners == 1, retired[member.OrgRole], parentResourceID)
 		if err != nil {
 			return nil, "", nil, err
 		}
 		ret = append(ret, resource)
 	}
 
-	nextCursor := ""
-	if client.HasNextPage(res) {
-		nextCursor = client.NextCursor(res)
+	return ret, page.NextCursor, annotations, nil
+}
+
+func (o *userBuilder) forgetOwners(orgID string) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	delete(o.owners, orgID)
+}
+
+// ownerCount returns the number of active owners of the organization, counted on the first call of a listing.
+func (o *userBuilder) ownerCount(ctx context.Context, orgID string) (int, error) {
+	o.mtx.Lock()
+	owners, ok := o.owners[orgID]
+	o.mtx.Unlock()
+	if ok {
+		return owners, nil
 	}
 
-	return ret, nextCursor, annotations, nil
+	owners, err := o.client.CountOrganizationOwners(ctx, orgID)
+	if err != nil {
+		return 0, fmt.Errorf("baton-sentry: failed to count organization owners: %w", err)
+	}
+
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.owners[orgID] = owners
+	return owners, nil
+}
+
+func (o *userBuilder) Get(ctx context.Context, resourceId *v2.ResourceId, parentResourceId *v2.ResourceId) (*v2.Resource, annotations.Annotations, error) {
+	userID := resourceId.Resource
+
+	var orgID string
+	if parentResourceId != nil {
+		orgID = parentResourceId.Resource
+	} else {
+		var err error
+		orgID, err = client.FindUserOrgID(ctx, o.client, userID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("baton-sentry: failed to find organization for user %s: %w", userID, err)
+		}
+	}
+
+	member, _, err := o.client.GetOrganizationMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("baton-sentry: failed to get organization member %s: %w", userID, err)
+	}
+
+	// The member details list the roles of the organization.
+	retired := client.RetiredOrgRoles(member.OrgRoleList)
+	resource, err := newUserResource(member.OrganizationMember(), member.IsOnlyOwner, retired[member.OrgRole], &v2.ResourceId{
+		ResourceType: organizationResourceType.Id,
+		Resource:     orgID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resource, nil, nil
 }
 
 // Entitlements always returns an empty slice for users.
@@ -101,6 +198,8 @@ func (o *userBuilder) CreateAccount(ctx context.Context, accountInfo *v2.Account
 	annotations.Annotations,
 	error,
 ) {
+	ctx, simulations := client.WithSimulations(ctx)
+
 	pMap := accountInfo.Profile.AsMap()
 	email, ok := pMap["email"].(string)
 	if !ok {
@@ -113,6 +212,10 @@ func (o *userBuilder) CreateAccount(ctx context.Context, accountInfo *v2.Account
 	}
 
 	orgRole, _ := pMap["orgRole"].(string)
+	if o.client.HasSCIM(ctx, orgId) {
+		return o.createSCIMAccount(ctx, orgId, email, orgRole, simulations)
+	}
+
 	err := o.client.AddMemberToOrganization(ctx, orgId, client.AddOrganizationMemberBody{
 		Email:   email,
 		OrgRole: orgRole,
@@ -121,26 +224,91 @@ func (o *userBuilder) CreateAccount(ctx context.Context, accountInfo *v2.Account
 		return nil, nil, nil, fmt.Errorf("baton-sentry: failed to create account: %w", err)
 	}
 
-	return &v2.CreateAccountResponse_ActionRequiredResult{}, nil, nil, nil
+	return &v2.CreateAccountResponse_ActionRequiredResult{}, nil, simulatedAnnotations(simulations), nil
+}
+
+// createSCIMAccount provisions the member through SCIM, they are a member right away instead of an invite.
+func (o *userBuilder) createSCIMAccount(ctx context.Context, orgID, email, orgRole string, simulations *client.Simulations) (
+	connectorbuilder.CreateAccountResponse,
+	[]*v2.PlaintextData,
+	annotations.Annotations,
+	error,
+) {
+	user, err := o.client.CreateSCIMUser(ctx, orgID, client.SCIMUser{
+		UserName:      email,
+		Emails:        []client.SCIMEmail{{Primary: true, Value: email, Type: "work"}},
+		Active:        true,
+		SentryOrgRole: orgRole,
+	})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("baton-sentry: failed to create account: %w", err)
+	}
+	// Dry runs don't create anything to look up.
+	if user.ID == "" {
+		return &v2.CreateAccountResponse_ActionRequiredResult{}, nil, simulatedAnnotations(simulations), nil
+	}
+
+	member, _, err := o.client.GetOrganizationMember(ctx, orgID, user.ID)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("baton-sentry: failed to get organization member %s: %w", user.ID, err)
+	}
+	// Retired roles can't be assigned, a new member never holds one.
+	resource, err := newUserResource(member.OrganizationMember(), member.IsOnlyOwner, false, &v2.ResourceId{
+		ResourceType: organizationResourceType.Id,
+		Resource:     orgID,
+	})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return &v2.CreateAccountResponse_SuccessResult{Resource: resource}, nil, simulatedAnnotations(simulations), nil
 }
 
 func (o *userBuilder) Delete(ctx context.Context, resourceId *v2.ResourceId) (annotations.Annotations, error) {
+	ctx, simulations := client.WithSimulations(ctx)
+
 	userID := resourceId.Resource
 	orgID, err := client.FindUserOrgID(ctx, o.client, userID)
 	if err != nil {
 		return nil, fmt.Errorf("baton-sentry: failed to find organization for user %s: %w", resourceId.Resource, err)
 	}
 
-	err = o.client.DeleteMemberFromOrganization(ctx, orgID, userID)
+	member, _, err := o.client.GetOrganizationMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("baton-sentry: failed to get organization member %s: %w", userID, err)
+	}
+	if err := checkNotOnlyOwner(member, orgID); err != nil {
+		return nil, err
+	}
+	if isManagedByIDP(ctx, o.client, orgID, member.Flags.IDPProvisioned) {
+		return nil, managedByIDPError("member", userID)
+	}
+
+	err = removeMember(ctx, o.client, orgID, userID)
+	if client.IsManagedByIdentityProvider(err) {
+		return nil, managedByIDPError("member", userID)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("baton-sentry: failed to delete user %s from organization %s: %w", userID, orgID, err)
 	}
 
-	return nil, nil
+	return simulatedAnnotations(simulations), nil
+}
+
+// checkNotOnlyOwner refuses to remove a member, or to take the owner role away from them, when they are the
+// only owner of the organization. Sentry rejects it too, with an error that doesn't say why.
+func checkNotOnlyOwner(member *client.DetailedMember, orgID string) error {
+	if !member.IsOnlyOwner {
+		return nil
+	}
+
+	return status.Errorf(codes.FailedPrecondition,
+		"baton-sentry: member %s is the only owner of organization %s, another owner must be added first", member.ID, orgID)
 }
 
 func newUserBuilder(client *client.Client) *userBuilder {
 	return &userBuilder{
 		client: client,
+		owners: map[string]int{},
 	}
 }
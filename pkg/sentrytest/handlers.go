@@ -0,0 +1,1372 @@
+package sentrytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+func (s *Server) listOrganizations(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	orgs := make([]client.Organization, 0, len(s.orgs))
+	for _, o := range s.orgs {
+		if !s.reaches(r, o) {
+			continue
+		}
+		// Like Sentry, the list leaves out the settings only found in the organization details.
+		org := o.org
+		org.OpenMembership = false
+		org.TrustedRelays = nil
+		org.OrgRoleList = nil
+		org.TeamRoleList = nil
+		orgs = append(orgs, org)
+	}
+
+	writePage(w, r, s.PageSize, orgs)
+}
+
+func (s *Server) getAvatar(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	a, ok := s.avatars[r.PathValue("avatar")]
+	if !ok || !s.reaches(r, a.org) {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return
+	}
+
+	w.Header().Set("Content-Type", a.contentType)
+	w.WriteHeader(http.StatusOK)
+	_, _ = w.Write(a.data)
+}
+
+func (s *Server) getOrganization(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, o.org)
+}
+
+// updateOrganization only supports replacing the trusted relays.
+func (s *Server) updateOrganization(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		TrustedRelays *[]client.TrustedRelay `json:"trustedRelays"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.TrustedRelays == nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	relays := make([]client.TrustedRelay, 0, len(*body.TrustedRelays))
+	for _, relay := range *body.TrustedRelays {
+		if relay.Name == "" || relay.PublicKey == "" {
+			writeJSON(w, http.StatusBadRequest, map[string][]string{"trustedRelays": {"Missing name or public key"}})
+			return
+		}
+		relay.Created = time.Now().UTC()
+		i := slices.IndexFunc(o.org.TrustedRelays, func(existing client.TrustedRelay) bool { return existing.PublicKey == relay.PublicKey })
+		if i >= 0 {
+			relay.Created = o.org.TrustedRelays[i].Created
+		}
+		relays = append(relays, relay)
+	}
+	o.org.TrustedRelays = relays
+
+	writeJSON(w, http.StatusOK, o.org)
+}
+
+func (s *Server) getAuthProvider(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	if o.authProvider == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	writeJSON(w, http.StatusOK, o.authProvider)
+}
+
+func (s *Server) listMembers(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	// Only the role:<role> and id:<member id> search terms are understood, any other term is ignored.
+	var role, id string
+	for _, term := range strings.Fields(r.URL.Query().Get("query")) {
+		if value, ok := strings.CutPrefix(term, "role:"); ok {
+			role = value
+		}
+		if value, ok := strings.CutPrefix(term, "id:"); ok {
+			id = value
+		}
+	}
+
+	expandExternalUsers := slices.Contains(r.URL.Query()["expand"], "externalUsers")
+	members := make([]client.OrganizationMember, 0, len(o.members))
+	for _, m := range o.members {
+		if role != "" && m.OrgRole != role {
+			continue
+		}
+		if id != "" && m.ID != id {
+			continue
+		}
+		member := m.OrganizationMember()
+		if expandExternalUsers {
+			member.ExternalUsers = o.memberExternalUsers(m)
+		}
+		members = append(members, member)
+	}
+
+	writePage(w, r, s.PageSize, members)
+}
+
+// addMember invites a member, Sentry refuses to invite an email that is already a member or invited.
+func (s *Server) addMember(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var body client.AddOrganizationMemberBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.Email == "" {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"email": {"This field is required."}})
+		return
+	}
+
+	if o.memberByEmail(body.Email) != nil {
+		writeJSON(w, http.StatusConflict, map[string][]string{
+			"email": {fmt.Sprintf("The user %s has already been invited", body.Email)},
+		})
+		return
+	}
+
+	member := s.newMember(o, body.Email, body.OrgRole)
+	member.Pending = true
+	member.InviteStatus = "approved"
+
+	writeJSON(w, http.StatusCreated, member)
+}
+
+func (s *Server) getMember(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	m, ok := lookupMember(w, r, o)
+	if !ok {
+		return
+	}
+
+	member := *m
+	member.IsOnlyOwner = o.isOnlyOwner(m)
+	member.OrgRoleList = o.org.OrgRoleList
+	member.TeamRoleList = o.org.TeamRoleList
+
+	writeJSON(w, http.StatusOK, member)
+}
+
+// updateMember changes the organization role of a member. Like Sentry, it refuses roles that are retired.
+func (s *Server) updateMember(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	m, ok := lookupMember(w, r, o)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		OrgRole string `json:"orgRole"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	i := slices.IndexFunc(o.org.OrgRoleList, func(role client.OrganizationRole) bool { return role.ID == body.OrgRole })
+	if i < 0 || o.org.OrgRoleList[i].IsRetired {
+		writeError(w, http.StatusBadRequest, "You do not have permission to set that org-level role")
+		return
+	}
+	if m.Flags.IDPRoleRestricted {
+		writeError(w, http.StatusForbidden, "This user's org-role is managed through your organization's identity provider.")
+		return
+	}
+	if o.isOnlyOwner(m) && body.OrgRole != client.OrgRoleOwner {
+		writeError(w, http.StatusForbidden, "You cannot demote the only remaining owner of the organization.")
+		return
+	}
+
+	m.OrgRole = body.OrgRole
+	m.Role = body.OrgRole
+
+	member := *m
+	member.IsOnlyOwner = o.isOnlyOwner(m)
+	writeJSON(w, http.StatusOK, member)
+}
+
+func (s *Server) deleteMember(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	m, ok := lookupMember(w, r, o)
+	if !ok {
+		return
+	}
+	if o.isOnlyOwner(m) {
+		writeError(w, http.StatusForbidden, "You cannot remove the only remaining owner of the organization.")
+		return
+	}
+	if m.Flags.IDPProvisioned {
+		writeError(w, http.StatusForbidden, "This user is managed through your organization's identity provider.")
+		return
+	}
+
+	for _, t := range o.teams {
+		leaveTeam(m, t)
+	}
+	for i, member := range o.members {
+		if member == m {
+			o.members = append(o.members[:i], o.members[i+1:]...)
+			break
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// addTeamMember answers 201 when the member joins the team and 204 when they already belonged to it.
+func (s *Server) addTeamMember(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	m, ok := lookupMember(w, r, o)
+	if !ok {
+		return
+	}
+	t, ok := lookupTeam(w, r, o)
+	if !ok {
+		return
+	}
+	if t.Flags.IDPProvisioned {
+		writeError(w, http.StatusForbidden, "This team is managed through your organization's identity provider.")
+		return
+	}
+
+	if !joinTeam(m, t) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, t)
+}
+
+func (s *Server) deleteTeamMember(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	m, ok := lookupMember(w, r, o)
+	if !ok {
+		return
+	}
+	t, ok := lookupTeam(w, r, o)
+	if !ok {
+		return
+	}
+	if t.Flags.IDPProvisioned {
+		writeError(w, http.StatusForbidden, "This team is managed through your organization's identity provider.")
+		return
+	}
+
+	leaveTeam(m, t)
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) listTeams(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	teams := make([]client.Team, 0, len(o.teams))
+	for _, t := range o.teams {
+		teams = append(teams, *t)
+	}
+
+	writePage(w, r, s.PageSize, teams)
+}
+
+func (s *Server) getTeam(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	t, ok := lookupTeam(w, r, o)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, t)
+}
+
+func (s *Server) listTeamMembers(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	t, ok := lookupTeam(w, r, o)
+	if !ok {
+		return
+	}
+
+	var members []client.TeamMember
+	for _, m := range o.members {
+		for _, role := range m.TeamRoles {
+			if role.TeamSlug != t.Slug {
+				continue
+			}
+			members = append(members, client.TeamMember{
+				ID:           m.ID,
+				Email:        m.Email,
+				Name:         m.Name,
+				OrgRole:      m.OrgRole,
+				Pending:      m.Pending,
+				Expired:      m.Expired,
+				DateCreated:  m.DateCreated,
+				InviteStatus: m.InviteStatus,
+				TeamRole:     role.Role,
+				TeamSlug:     role.TeamSlug,
+			})
+		}
+	}
+
+	writePage(w, r, s.PageSize, members)
+}
+
+func (s *Server) listTeamProjects(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	t, ok := lookupTeam(w, r, o)
+	if !ok {
+		return
+	}
+
+	var projects []client.Project
+	for _, p := range o.projects {
+		if hasProjectTeam(p, t) {
+			projects = append(projects, p.Project())
+		}
+	}
+
+	writePage(w, r, s.PageSize, projects)
+}
+
+func (s *Server) listProjects(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	projects := make([]client.Project, 0, len(o.projects))
+	for _, p := range o.projects {
+		projects = append(projects, p.Project())
+	}
+
+	writePage(w, r, s.PageSize, projects)
+}
+
+func (s *Server) getProject(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	p, ok := lookupProject(w, r, o)
+	if !ok {
+		return
+	}
+
+	// Plugins come without their configuration in the project details.
+	project := *p
+	project.Plugins = make([]client.Plugin, 0, len(p.Plugins))
+	for _, plugin := range p.Plugins {
+		plugin.Config = nil
+		project.Plugins = append(project.Plugins, plugin)
+	}
+
+	writeJSON(w, http.StatusOK, project)
+}
+
+func (s *Server) listServiceHooks(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	p, ok := lookupProject(w, r, o)
+	if !ok {
+		return
+	}
+
+	hooks := []client.ServiceHook{}
+	for _, hook := range o.serviceHooks {
+		if hook.project == p.ID {
+			hooks = append(hooks, hook.hook)
+		}
+	}
+
+	writePage(w, r, s.PageSize, hooks)
+}
+
+func (s *Server) getServiceHook(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	p, ok := lookupProject(w, r, o)
+	if !ok {
+		return
+	}
+
+	for _, hook := range o.serviceHooks {
+		if hook.project == p.ID && hook.hook.ID == r.PathValue("hook") {
+			writeJSON(w, http.StatusOK, hook.hook)
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "The requested resource does not exist")
+}
+
+func (s *Server) getPlugin(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	p, ok := lookupProject(w, r, o)
+	if !ok {
+		return
+	}
+
+	i := slices.IndexFunc(p.Plugins, func(plugin client.Plugin) bool { return plugin.Slug == r.PathValue("plugin") })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "Plugin not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, p.Plugins[i])
+}
+
+// listProjectMembers lists the active members of any team with access to the project.
+func (s *Server) listProjectMembers(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	p, ok := lookupProject(w, r, o)
+	if !ok {
+		return
+	}
+
+	var members []client.ProjectMember
+	for _, m := range o.members {
+		if m.Pending {
+			continue
+		}
+		for _, team := range p.Teams {
+			if t := o.team(team.ID); t != nil && isTeamMember(m, t) {
+				members = append(members, client.ProjectMember(m.OrganizationMember()))
+				break
+			}
+		}
+	}
+
+	writePage(w, r, s.PageSize, members)
+}
+
+func (s *Server) addProjectTeam(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	p, ok := lookupProject(w, r, o)
+	if !ok {
+		return
+	}
+	t, ok := lookupTeam(w, r, o)
+	if !ok {
+		return
+	}
+
+	addProjectTeam(p, t)
+
+	writeJSON(w, http.StatusCreated, p)
+}
+
+func (s *Server) deleteProjectTeam(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	p, ok := lookupProject(w, r, o)
+	if !ok {
+		return
+	}
+	t, ok := lookupTeam(w, r, o)
+	if !ok {
+		return
+	}
+
+	for i, team := range p.Teams {
+		if team.ID == t.ID {
+			p.Teams = append(p.Teams[:i], p.Teams[i+1:]...)
+			break
+		}
+	}
+
+	writeJSON(w, http.StatusOK, p)
+}
+
+func (s *Server) deleteExternalUser(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	i := slices.IndexFunc(o.externalUsers, func(e *client.ExternalUser) bool { return e.ID == r.PathValue("externalUser") })
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return
+	}
+	o.externalUsers = slices.Delete(o.externalUsers, i, i+1)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// listIssues understands the assigned:<email> and assigned:#<team slug> search terms, any other term is ignored.
+func (s *Server) listIssues(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var assigned string
+	for _, term := range strings.Fields(r.URL.Query().Get("query")) {
+		if value, ok := strings.CutPrefix(term, "assigned:"); ok {
+			assigned = value
+		}
+	}
+
+	issues := []client.Issue{}
+	for _, i := range o.issues {
+		if assigned != "" && !isAssigned(o, i, assigned) {
+			continue
+		}
+		issues = append(issues, *i)
+	}
+
+	writePage(w, r, s.PageSize, issues)
+}
+
+func isAssigned(o *organization, i *client.Issue, assigned string) bool {
+	if i.AssignedTo == nil {
+		return false
+	}
+	if slug, ok := strings.CutPrefix(assigned, "#"); ok {
+		t := o.team(slug)
+		return t != nil && i.AssignedTo.Type == "team" && i.AssignedTo.ID == t.ID
+	}
+	return i.AssignedTo.Type == "user" && i.AssignedTo.Email == assigned
+}
+
+// updateIssues bulk updates the issues given by the id parameters, only assignedTo is supported.
+func (s *Server) updateIssues(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		AssignedTo string `json:"assignedTo"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	var assignee *client.Actor
+	if body.AssignedTo != "" {
+		if assignee, ok = o.actor(body.AssignedTo); !ok {
+			writeJSON(w, http.StatusBadRequest, map[string][]string{"assignedTo": {"Unknown actor input"}})
+			return
+		}
+	}
+
+	ids := r.URL.Query()["id"]
+	if len(ids) == 0 {
+		writeError(w, http.StatusBadRequest, "You must specify a list of IDs for this operation")
+		return
+	}
+	for _, id := range ids {
+		if i := o.issue(id); i != nil {
+			i.AssignedTo = assignee
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{"assignedTo": assignee})
+}
+
+func (s *Server) listMetricAlertRules(w http.ResponseWriter, r *http.Request) {
+	s.listAlertRules(w, r, false)
+}
+
+func (s *Server) listIssueAlertRules(w http.ResponseWriter, r *http.Request) {
+	s.listAlertRules(w, r, true)
+}
+
+func (s *Server) getMetricAlertRule(w http.ResponseWriter, r *http.Request) {
+	s.getAlertRule(w, r, false)
+}
+
+func (s *Server) getIssueAlertRule(w http.ResponseWriter, r *http.Request) {
+	s.getAlertRule(w, r, true)
+}
+
+func (s *Server) updateMetricAlertRule(w http.ResponseWriter, r *http.Request) {
+	s.updateAlertRule(w, r, false)
+}
+
+func (s *Server) updateIssueAlertRule(w http.ResponseWriter, r *http.Request) {
+	s.updateAlertRule(w, r, true)
+}
+
+func (s *Server) listAlertRules(w http.ResponseWriter, r *http.Request, issueRules bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	var project string
+	if issueRules {
+		p, ok := lookupProject(w, r, o)
+		if !ok {
+			return
+		}
+		project = p.Slug
+	}
+
+	rules := []client.AlertRule{}
+	for _, rule := range o.alertRules {
+		if rule.project == project {
+			rules = append(rules, rule.rule)
+		}
+	}
+
+	writePage(w, r, s.PageSize, rules)
+}
+
+func (s *Server) getAlertRule(w http.ResponseWriter, r *http.Request, issueRule bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	rule, ok := lookupAlertRule(w, r, o, issueRule)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, rule.rule)
+}
+
+// updateAlertRule replaces an alert rule, like Sentry it refuses bodies that are not a complete rule.
+func (s *Server) updateAlertRule(w http.ResponseWriter, r *http.Request, issueRule bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	rule, ok := lookupAlertRule(w, r, o, issueRule)
+	if !ok {
+		return
+	}
+
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if _, ok := body["name"]; !ok {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"name": {"This field is required."}})
+		return
+	}
+
+	var owner *string
+	if err := json.Unmarshal(body["owner"], &owner); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"owner": {"Could not parse actor."}})
+		return
+	}
+	if owner != nil {
+		if _, ok := o.actor(*owner); !ok {
+			writeJSON(w, http.StatusBadRequest, map[string][]string{"owner": {"Could not parse actor."}})
+			return
+		}
+	}
+	rule.rule.Owner = owner
+
+	writeJSON(w, http.StatusOK, rule.rule)
+}
+
+func (s *Server) listMonitors(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	monitors := make([]client.Monitor, 0, len(o.monitors))
+	for _, m := range o.monitors {
+		monitors = append(monitors, *m)
+	}
+
+	writePage(w, r, s.PageSize, monitors)
+}
+
+func (s *Server) getMonitor(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	m := o.monitor(r.PathValue("monitor"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, m)
+}
+
+// updateMonitor applies a partial update, only the owner is supported.
+func (s *Server) updateMonitor(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	m := o.monitor(r.PathValue("monitor"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return
+	}
+
+	if !updateOwner(w, r, o, &m.Owner) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (s *Server) listUptimeMonitors(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	monitors := make([]client.UptimeMonitor, 0, len(o.uptimeMonitors))
+	for _, m := range o.uptimeMonitors {
+		monitors = append(monitors, *m)
+	}
+
+	writePage(w, r, s.PageSize, monitors)
+}
+
+func (s *Server) getUptimeMonitor(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	m, ok := lookupUptimeMonitor(w, r, o)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, m)
+}
+
+// updateUptimeMonitor applies a partial update, only the owner is supported.
+func (s *Server) updateUptimeMonitor(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	m, ok := lookupUptimeMonitor(w, r, o)
+	if !ok {
+		return
+	}
+
+	if !updateOwner(w, r, o, &m.Owner) {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (s *Server) listAccessRequests(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	requests := []client.AccessRequest{}
+	for _, request := range o.accessRequests {
+		requests = append(requests, *request)
+	}
+
+	writeJSON(w, http.StatusOK, requests)
+}
+
+// reviewAccessRequest adds the member to the team when the request is approved, the request is gone either way.
+func (s *Server) reviewAccessRequest(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	i := slices.IndexFunc(o.accessRequests, func(request *client.AccessRequest) bool {
+		return request.ID == r.PathValue("request")
+	})
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return
+	}
+
+	var body struct {
+		IsApproved *bool `json:"isApproved"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.IsApproved == nil {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"isApproved": {"This field is required."}})
+		return
+	}
+
+	request := o.accessRequests[i]
+	if *body.IsApproved {
+		m, t := o.member(request.Member.ID), o.team(request.Team.ID)
+		if m != nil && t != nil {
+			joinTeam(m, t)
+		}
+	}
+	o.accessRequests = slices.Delete(o.accessRequests, i, i+1)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) listInviteRequests(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	requests := make([]client.InviteRequest, 0, len(o.inviteRequests))
+	for _, request := range o.inviteRequests {
+		requests = append(requests, inviteRequest(request))
+	}
+
+	writePage(w, r, s.PageSize, requests)
+}
+
+func (s *Server) getInviteRequest(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	i, ok := lookupInviteRequest(w, r, o)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, inviteRequest(o.inviteRequests[i]))
+}
+
+// approveInviteRequest turns the request into a pending invite.
+func (s *Server) approveInviteRequest(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	i, ok := lookupInviteRequest(w, r, o)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Approve bool `json:"approve"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !body.Approve {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"approve": {"This field is required."}})
+		return
+	}
+
+	m := o.inviteRequests[i]
+	o.inviteRequests = slices.Delete(o.inviteRequests, i, i+1)
+	m.InviteStatus = "approved"
+	o.members = append(o.members, m)
+
+	writeJSON(w, http.StatusOK, m)
+}
+
+func (s *Server) denyInviteRequest(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	i, ok := lookupInviteRequest(w, r, o)
+	if !ok {
+		return
+	}
+
+	o.inviteRequests = slices.Delete(o.inviteRequests, i, i+1)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// getProjectOwnership returns the ownership rules of a project, projects that never had any have an empty
+// configuration.
+func (s *Server) getProjectOwnership(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	p, ok := lookupProject(w, r, o)
+	if !ok {
+		return
+	}
+
+	ownership, ok := o.ownership[p.ID]
+	if !ok {
+		ownership = &client.ProjectOwnership{FallThrough: true, AutoAssignment: "Auto Assign to Issue Owner"}
+	}
+
+	writeJSON(w, http.StatusOK, ownership)
+}
+
+func (s *Server) listProjectCodeOwners(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	p, ok := lookupProject(w, r, o)
+	if !ok {
+		return
+	}
+	if o.codeownersDisabled {
+		writeError(w, http.StatusForbidden, "You do not have permission to perform this action.")
+		return
+	}
+
+	expand := r.URL.Query().Get("expand") == "ownershipSyntax"
+	codeowners := []client.CodeOwners{}
+	for _, c := range o.codeowners {
+		if c.project != p.ID {
+			continue
+		}
+		item := c.codeowners
+		if !expand {
+			item.OwnershipSyntax = ""
+		}
+		codeowners = append(codeowners, item)
+	}
+
+	writeJSON(w, http.StatusOK, codeowners)
+}
+
+// listDashboards lists the custom dashboards after the prebuilt one Sentry always lists first.
+func (s *Server) listDashboards(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	dashboards := []client.Dashboard{{ID: "default-overview", Title: "General"}}
+	for _, d := range o.dashboards {
+		dashboards = append(dashboards, *d)
+	}
+
+	writePage(w, r, s.PageSize, dashboards)
+}
+
+func (s *Server) getDashboard(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	d, ok := lookupDashboard(w, r, o)
+	if !ok {
+		return
+	}
+
+	writeJSON(w, http.StatusOK, d)
+}
+
+// updateDashboard replaces the dashboard, only the title and the permissions are kept.
+func (s *Server) updateDashboard(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	d, ok := lookupDashboard(w, r, o)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Title       string                       `json:"title"`
+		Permissions *client.DashboardPermissions `json:"permissions"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+	if body.Title == "" {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"title": {"This field is required."}})
+		return
+	}
+	if body.Permissions != nil {
+		for _, teamID := range body.Permissions.TeamsWithEditAccess {
+			if o.team(strconv.Itoa(teamID)) == nil {
+				writeJSON(w, http.StatusBadRequest, map[string][]string{"permissions": {"Invalid team."}})
+				return
+			}
+		}
+	}
+
+	d.Title = body.Title
+	d.Permissions = body.Permissions
+
+	writeJSON(w, http.StatusOK, d)
+}
+
+// updateOwner sets owner from the owner field of a partial update, leaving it alone when the field is missing.
+func updateOwner(w http.ResponseWriter, r *http.Request, o *organization, owner **client.Actor) bool {
+	var body map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+	raw, ok := body["owner"]
+	if !ok {
+		return true
+	}
+
+	var actor *string
+	if err := json.Unmarshal(raw, &actor); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"owner": {"Could not parse actor."}})
+		return false
+	}
+	if actor == nil {
+		*owner = nil
+		return true
+	}
+
+	resolved, ok := o.actor(*actor)
+	if !ok {
+		writeJSON(w, http.StatusBadRequest, map[string][]string{"owner": {"Could not parse actor."}})
+		return false
+	}
+	*owner = resolved
+
+	return true
+}
+
+func (s *Server) lookupOrg(w http.ResponseWriter, r *http.Request) (*organization, bool) {
+	o := s.org(r.PathValue("org"))
+	if o == nil {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return nil, false
+	}
+	if !s.reaches(r, o) {
+		writeError(w, http.StatusForbidden, "You do not have permission to perform this action.")
+		return nil, false
+	}
+	return o, true
+}
+
+func lookupMember(w http.ResponseWriter, r *http.Request, o *organization) (*client.DetailedMember, bool) {
+	m := o.member(r.PathValue("member"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return nil, false
+	}
+	return m, true
+}
+
+func lookupTeam(w http.ResponseWriter, r *http.Request, o *organization) (*client.Team, bool) {
+	t := o.team(r.PathValue("team"))
+	if t == nil {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return nil, false
+	}
+	return t, true
+}
+
+func lookupProject(w http.ResponseWriter, r *http.Request, o *organization) (*client.DetailedProject, bool) {
+	p := o.project(r.PathValue("project"))
+	if p == nil {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return nil, false
+	}
+	return p, true
+}
+
+// lookupAlertRule finds the rule of the request, issue alert rules are looked up in the request's project.
+func lookupAlertRule(w http.ResponseWriter, r *http.Request, o *organization, issueRule bool) (*alertRule, bool) {
+	var project string
+	if issueRule {
+		p, ok := lookupProject(w, r, o)
+		if !ok {
+			return nil, false
+		}
+		project = p.Slug
+	}
+
+	rule := o.alertRule(project, r.PathValue("rule"))
+	if rule == nil || (rule.project == "") == issueRule {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return nil, false
+	}
+	return rule, true
+}
+
+func lookupUptimeMonitor(w http.ResponseWriter, r *http.Request, o *organization) (*client.UptimeMonitor, bool) {
+	p, ok := lookupProject(w, r, o)
+	if !ok {
+		return nil, false
+	}
+
+	m := o.uptimeMonitor(p.Slug, r.PathValue("monitor"))
+	if m == nil {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return nil, false
+	}
+	return m, true
+}
+
+func lookupInviteRequest(w http.ResponseWriter, r *http.Request, o *organization) (int, bool) {
+	i := slices.IndexFunc(o.inviteRequests, func(m *client.DetailedMember) bool {
+		return m.ID == r.PathValue("member")
+	})
+	if i < 0 {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return 0, false
+	}
+	return i, true
+}
+
+func lookupDashboard(w http.ResponseWriter, r *http.Request, o *organization) (*client.Dashboard, bool) {
+	d := o.dashboard(r.PathValue("dashboard"))
+	if d == nil {
+		writeError(w, http.StatusNotFound, "The requested resource does not exist")
+		return nil, false
+	}
+	return d, true
+}
+
+// writePage writes one page of items, with Sentry's Link header pointing at the previous and next pages.
+// Cursors have Sentry's "<value>:<offset>:<is_prev>" shape, only the offset is used.
+//
+// https://docs.sentry.io/api/pagination/
+func writePage[T any](w http.ResponseWriter, r *http.Request, pageSize int, items []T) {
+	limit := pageSize
+	if perPage, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil && perPage > 0 {
+		limit = min(perPage, pageSize)
+	}
+
+	offset := 0
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		parts := strings.Split(cursor, ":")
+		if len(parts) != 3 {
+			writeError(w, http.StatusBadRequest, "Invalid cursor parameter.")
+			return
+		}
+		n, err := strconv.Atoi(parts[1])
+		if err != nil || n < 0 {
+			writeError(w, http.StatusBadRequest, "Invalid cursor parameter.")
+			return
+		}
+		offset = n
+	}
+
+	start := min(offset, len(items))
+	end := min(start+limit, len(items))
+
+	prev := fmt.Sprintf("0:%d:1", max(start-limit, 0))
+	next := fmt.Sprintf("0:%d:0", end)
+	w.Header().Set("Link", strings.Join([]string{
+		pageLink(r, prev, "previous", start > 0),
+		pageLink(r, next, "next", end < len(items)),
+	}, ", "))
+
+	page := items[start:end]
+	if page == nil {
+		page = []T{}
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func pageLink(r *http.Request, cursor, rel string, results bool) string {
+	q := r.URL.Query()
+	q.Set("cursor", cursor)
+	u := url.URL{Scheme: "http", Host: r.Host, Path: r.URL.Path, RawQuery: q.Encode()}
+
+	return fmt.Sprintf(`<%s>; rel="%s"; results="%t"; cursor="%s"`, u.String(), rel, results, cursor)
+}
@@ -0,0 +1,183 @@
+package sentrytest
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"slices"
+
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+// The SCIM endpoints only support what the connector sends: creating users, deactivating them, and adding or
+// removing group members.
+
+const scimErrorSchema = "urn:ietf:params:scim:api:messages:2.0:Error"
+
+// scimMemberFilter matches the path of a group member removal, e.g. members[value eq "42"].
+var scimMemberFilter = regexp.MustCompile(`^members\[value eq "([^"]+)"\]$`)
+
+func writeSCIMError(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]any{
+		"schemas": []string{scimErrorSchema},
+		"detail":  detail,
+		"status":  status,
+	})
+}
+
+// createSCIMUser adds an active member provisioned by the identity provider.
+func (s *Server) createSCIMUser(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+
+	var body client.SCIMUser
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || body.UserName == "" {
+		writeSCIMError(w, http.StatusBadRequest, "userName is required")
+		return
+	}
+	if !slices.Contains(body.Schemas, client.SCIMUserSchema) {
+		writeSCIMError(w, http.StatusBadRequest, "Invalid schemas")
+		return
+	}
+	if o.memberByEmail(body.UserName) != nil {
+		writeSCIMError(w, http.StatusConflict, "User already exists in the database.")
+		return
+	}
+
+	m := s.newMember(o, body.UserName, body.SentryOrgRole)
+	m.User = &client.DetailedMemberUser{
+		ID:       s.newID(),
+		Name:     body.UserName,
+		Username: body.UserName,
+		Email:    body.UserName,
+		IsActive: true,
+	}
+	m.InviteStatus = "approved"
+	m.Flags.IDPProvisioned = true
+
+	writeJSON(w, http.StatusCreated, client.SCIMUser{
+		Schemas:       []string{client.SCIMUserSchema},
+		ID:            m.ID,
+		UserName:      body.UserName,
+		Emails:        body.Emails,
+		Active:        true,
+		SentryOrgRole: m.OrgRole,
+	})
+}
+
+// patchSCIMUser only supports deactivation, which removes the member.
+func (s *Server) patchSCIMUser(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	operations, ok := decodeSCIMPatch(w, r)
+	if !ok {
+		return
+	}
+	i := slices.IndexFunc(o.members, func(m *client.DetailedMember) bool { return m.ID == r.PathValue("member") })
+	if i < 0 {
+		writeSCIMError(w, http.StatusNotFound, "User not found.")
+		return
+	}
+
+	for _, op := range operations {
+		var value struct {
+			Active *bool `json:"active"`
+		}
+		if op.Op != "replace" || json.Unmarshal(op.Value, &value) != nil || value.Active == nil || *value.Active {
+			writeSCIMError(w, http.StatusBadRequest, "Invalid Operation")
+			return
+		}
+	}
+
+	m := o.members[i]
+	for _, t := range o.teams {
+		leaveTeam(m, t)
+	}
+	o.members = slices.Delete(o.members, i, i+1)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// patchSCIMGroup adds and removes team members. Groups are addressed by team ID only.
+func (s *Server) patchSCIMGroup(w http.ResponseWriter, r *http.Request) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o, ok := s.lookupOrg(w, r)
+	if !ok {
+		return
+	}
+	operations, ok := decodeSCIMPatch(w, r)
+	if !ok {
+		return
+	}
+	i := slices.IndexFunc(o.teams, func(t *client.Team) bool { return t.ID == r.PathValue("team") })
+	if i < 0 {
+		writeSCIMError(w, http.StatusNotFound, "Group not found.")
+		return
+	}
+	t := o.teams[i]
+
+	for _, op := range operations {
+		switch op.Op {
+		case "add":
+			var members []struct {
+				Value string `json:"value"`
+			}
+			if op.Path != "members" || json.Unmarshal(op.Value, &members) != nil {
+				writeSCIMError(w, http.StatusBadRequest, "Invalid Operation")
+				return
+			}
+			for _, member := range members {
+				m := o.member(member.Value)
+				if m == nil {
+					writeSCIMError(w, http.StatusBadRequest, "Member not found.")
+					return
+				}
+				joinTeam(m, t)
+			}
+		case "remove":
+			match := scimMemberFilter.FindStringSubmatch(op.Path)
+			if match == nil {
+				writeSCIMError(w, http.StatusBadRequest, "Invalid Operation")
+				return
+			}
+			if m := o.member(match[1]); m != nil {
+				leaveTeam(m, t)
+			}
+		default:
+			writeSCIMError(w, http.StatusBadRequest, "Invalid Operation")
+			return
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+type scimOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+func decodeSCIMPatch(w http.ResponseWriter, r *http.Request) ([]scimOperation, bool) {
+	var body struct {
+		Schemas    []string        `json:"schemas"`
+		Operations []scimOperation `json:"Operations"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil || !slices.Contains(body.Schemas, client.SCIMPatchSchema) {
+		writeSCIMError(w, http.StatusBadRequest, "Invalid schemas")
+		return nil, false
+	}
+	return body.Operations, true
+}
@@ -0,0 +1,1293 @@
+// Package sentrytest provides an in-process stand-in for the Sentry API, for tests that exercise the client
+// and the connector end to end.
+//
+// The server models organizations, members, pending invites, teams, projects, issues, alert rules, monitors,
+// external users and trusted relays, and answers the endpoints the connector uses the way Sentry does: cursors in
+// Link headers, rate limit headers on every response and JSON error bodies.
+package sentrytest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/conductorone/baton-sentry/pkg/client"
+)
+
+// Token is the API token the server accepts for every organization not restricted with RestrictOrganization. More
+// tokens can be added with AddToken.
+const Token = "sentry-test-token"
+
+// SCIMToken is the only token the SCIM endpoints accept, for every organization.
+const SCIMToken = "sentry-test-scim-token"
+
+const (
+	defaultPageSize  = 100
+	defaultRateLimit = 40
+)
+
+// orgRoles and teamRoles are the roles of every organization, as on sentry.io where the admin organization role
+// is retired in favor of team roles.
+var (
+	orgRoles = []client.OrganizationRole{
+		{
+			ID:                 "member",
+			Name:               "Member",
+			Desc:               "Members can view and act on events, as well as view most other data within the organization.",
+			Scopes:             []string{"alerts:read", "alerts:write", "event:admin", "event:read", "event:write", "member:read", "org:read", "project:read", "project:releases", "team:read"},
+			IsTeamRolesAllowed: true,
+			MinimumTeamRole:    "contributor",
+		},
+		{
+			ID:              "admin",
+			Name:            "Admin",
+			Desc:            "Admin privileges on any teams of which they're a member.",
+			Scopes:          []string{"alerts:read", "alerts:write", "event:admin", "event:read", "event:write", "member:read", "org:read", "project:admin", "project:read", "project:releases", "project:write", "team:admin", "team:read", "team:write"},
+			IsRetired:       true,
+			MinimumTeamRole: "admin",
+		},
+		{
+			ID:       "manager",
+			Name:     "Manager",
+			Desc:     "Gains admin access on all teams as well as the ability to add and remove members.",
+			Scopes:   []string{"alerts:read", "alerts:write", "event:admin", "event:read", "event:write", "member:admin", "member:invite", "member:read", "member:write", "org:integrations", "org:read", "project:admin", "project:read", "project:releases", "project:write", "team:admin", "team:read", "team:write"},
+			IsGlobal: true, IsGlobalAlt: true, IsTeamRolesAllowed: true,
+			MinimumTeamRole: "admin",
+		},
+		{
+			ID:       "owner",
+			Name:     "Owner",
+			Desc:     "Unrestricted access to the organization, its data, and its settings.",
+			Scopes:   []string{"alerts:read", "alerts:write", "event:admin", "event:read", "event:write", "member:admin", "member:invite", "member:read", "member:write", "org:admin", "org:billing", "org:integrations", "org:read", "org:write", "project:admin", "project:read", "project:releases", "project:write", "team:admin", "team:read", "team:write"},
+			IsGlobal: true, IsGlobalAlt: true, IsTeamRolesAllowed: true,
+			MinimumTeamRole: "admin",
+		},
+		{
+			ID:              "billing",
+			Name:            "Billing",
+			Desc:            "Can manage payment and compliance details.",
+			Scopes:          []string{"org:billing"},
+			MinimumTeamRole: "contributor",
+		},
+	}
+	teamRoles = []client.TeamRole{
+		{
+			ID:     "contributor",
+			Name:   "Contributor",
+			Desc:   "Contributors can view and act on events, as well as view most other data within the team's projects.",
+			Scopes: []string{"alerts:read", "alerts:write", "event:admin", "event:read", "event:write", "member:read", "org:read", "project:read", "project:releases", "team:read"},
+		},
+		{
+			ID:     "admin",
+			Name:   "Team Admin",
+			Desc:   "Admin privileges on the team as well as the scopes for the Contributor Role.",
+			Scopes: []string{"alerts:read", "alerts:write", "event:admin", "event:read", "event:write", "member:read", "org:read", "project:admin", "project:read", "project:releases", "project:write", "team:admin", "team:read", "team:write"},
+		},
+	}
+)
+
+type organization struct {
+	org client.Organization
+	// restricted organizations are only reachable with the tokens added for them.
+	restricted     bool
+	authProvider   *client.AuthProvider
+	members        []*client.DetailedMember
+	teams          []*client.Team
+	projects       []*client.DetailedProject
+	issues         []*client.Issue
+	alertRules     []*alertRule
+	monitors       []*client.Monitor
+	uptimeMonitors []*client.UptimeMonitor
+	externalUsers  []*client.ExternalUser
+	dashboards     []*client.Dashboard
+	// ownership holds the ownership rules of each project by project ID.
+	ownership  map[string]*client.ProjectOwnership
+	codeowners []*codeOwners
+	// codeownersDisabled answers 403 for CODEOWNERS, like Sentry does for plans without the feature.
+	codeownersDisabled bool
+	// inviteRequests are invites waiting for a manager's approval, they aren't members yet.
+	inviteRequests []*client.DetailedMember
+	accessRequests []*client.AccessRequest
+	serviceHooks   []*serviceHook
+}
+
+// avatar is an uploaded image, only served to the tokens reaching its organization.
+type avatar struct {
+	org         *organization
+	contentType string
+	data        []byte
+}
+
+type serviceHook struct {
+	hook    client.ServiceHook
+	project string
+}
+
+type codeOwners struct {
+	codeowners client.CodeOwners
+	project    string
+}
+
+// alertRule is a metric alert rule when project is empty, an issue alert rule of that project otherwise.
+type alertRule struct {
+	rule    client.AlertRule
+	project string
+}
+
+// Server is a fake Sentry API. The fixture methods panic on unknown organizations, teams or projects,
+// since that is always a mistake in the test setup.
+type Server struct {
+	*httptest.Server
+
+	// PageSize caps the number of items on each page, whatever per_page the client asks for.
+	PageSize int
+	// RateLimit is reported in the rate limit headers of every response.
+	RateLimit int
+
+	mtx  sync.Mutex
+	orgs []*organization
+	// tokens maps the tokens added with AddToken to the slugs of the organizations they reach.
+	tokens map[string][]string
+	// avatars holds the uploaded avatars by UUID.
+	avatars   map[string]*avatar
+	nextID    int
+	throttled int
+	requests  []string
+}
+
+// NewServer starts a server with no data, it is closed when the test finishes.
+func NewServer(t interface{ Cleanup(func()) }) *Server {
+	s := &Server{
+		PageSize:  defaultPageSize,
+		RateLimit: defaultRateLimit,
+		tokens:    map[string][]string{},
+		avatars:   map[string]*avatar{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/0/organizations/{$}", s.listOrganizations)
+	mux.HandleFunc("GET /api/0/organizations/{org}/{$}", s.getOrganization)
+	mux.HandleFunc("PUT /api/0/organizations/{org}/{$}", s.updateOrganization)
+	mux.HandleFunc("GET /api/0/organizations/{org}/auth-provider/{$}", s.getAuthProvider)
+	mux.HandleFunc("GET /api/0/organizations/{org}/members/{$}", s.listMembers)
+	mux.HandleFunc("POST /api/0/organizations/{org}/members/{$}", s.addMember)
+	mux.HandleFunc("GET /api/0/organizations/{org}/members/{member}/{$}", s.getMember)
+	mux.HandleFunc("PUT /api/0/organizations/{org}/members/{member}/{$}", s.updateMember)
+	mux.HandleFunc("DELETE /api/0/organizations/{org}/members/{member}/{$}", s.deleteMember)
+	mux.HandleFunc("POST /api/0/organizations/{org}/members/{member}/teams/{team}/{$}", s.addTeamMember)
+	mux.HandleFunc("DELETE /api/0/organizations/{org}/members/{member}/teams/{team}/{$}", s.deleteTeamMember)
+	mux.HandleFunc("DELETE /api/0/organizations/{org}/external-users/{externalUser}/{$}", s.deleteExternalUser)
+	mux.HandleFunc("GET /api/0/organizations/{org}/issues/{$}", s.listIssues)
+	mux.HandleFunc("PUT /api/0/organizations/{org}/issues/{$}", s.updateIssues)
+	mux.HandleFunc("GET /api/0/organizations/{org}/alert-rules/{$}", s.listMetricAlertRules)
+	mux.HandleFunc("GET /api/0/organizations/{org}/alert-rules/{rule}/{$}", s.getMetricAlertRule)
+	mux.HandleFunc("PUT /api/0/organizations/{org}/alert-rules/{rule}/{$}", s.updateMetricAlertRule)
+	mux.HandleFunc("GET /api/0/organizations/{org}/monitors/{$}", s.listMonitors)
+	mux.HandleFunc("GET /api/0/organizations/{org}/monitors/{monitor}/{$}", s.getMonitor)
+	mux.HandleFunc("PUT /api/0/organizations/{org}/monitors/{monitor}/{$}", s.updateMonitor)
+	mux.HandleFunc("GET /api/0/organizations/{org}/uptime/{$}", s.listUptimeMonitors)
+	mux.HandleFunc("GET /api/0/organizations/{org}/dashboards/{$}", s.listDashboards)
+	mux.HandleFunc("GET /api/0/organizations/{org}/dashboards/{dashboard}/{$}", s.getDashboard)
+	mux.HandleFunc("PUT /api/0/organizations/{org}/dashboards/{dashboard}/{$}", s.updateDashboard)
+	mux.HandleFunc("GET /api/0/organizations/{org}/access-requests/{$}", s.listAccessRequests)
+	mux.HandleFunc("PUT /api/0/organizations/{org}/access-requests/{request}/{$}", s.reviewAccessRequest)
+	mux.HandleFunc("GET /api/0/organizations/{org}/invite-requests/{$}", s.listInviteRequests)
+	mux.HandleFunc("GET /api/0/organizations/{org}/invite-requests/{member}/{$}", s.getInviteRequest)
+	mux.HandleFunc("PUT /api/0/organizations/{org}/invite-requests/{member}/{$}", s.approveInviteRequest)
+	mux.HandleFunc("DELETE /api/0/organizations/{org}/invite-requests/{member}/{$}", s.denyInviteRequest)
+	mux.HandleFunc("POST /api/0/organizations/{org}/scim/v2/Users", s.createSCIMUser)
+	mux.HandleFunc("PATCH /api/0/organizations/{org}/scim/v2/Users/{member}", s.patchSCIMUser)
+	mux.HandleFunc("PATCH /api/0/organizations/{org}/scim/v2/Groups/{team}", s.patchSCIMGroup)
+	mux.HandleFunc("GET /api/0/organizations/{org}/teams/{$}", s.listTeams)
+	mux.HandleFunc("GET /api/0/organizations/{org}/projects/{$}", s.listProjects)
+	mux.HandleFunc("GET /api/0/teams/{org}/{team}/{$}", s.getTeam)
+	mux.HandleFunc("GET /api/0/teams/{org}/{team}/members/{$}", s.listTeamMembers)
+	mux.HandleFunc("GET /api/0/teams/{org}/{team}/projects/{$}", s.listTeamProjects)
+	mux.HandleFunc("GET /api/0/projects/{org}/{project}/{$}", s.getProject)
+	mux.HandleFunc("GET /api/0/projects/{org}/{project}/members/{$}", s.listProjectMembers)
+	mux.HandleFunc("POST /api/0/projects/{org}/{project}/teams/{team}/{$}", s.addProjectTeam)
+	mux.HandleFunc("DELETE /api/0/projects/{org}/{project}/teams/{team}/{$}", s.deleteProjectTeam)
+	mux.HandleFunc("GET /api/0/projects/{org}/{project}/ownership/{$}", s.getProjectOwnership)
+	mux.HandleFunc("GET /api/0/projects/{org}/{project}/codeowners/{$}", s.listProjectCodeOwners)
+	mux.HandleFunc("GET /api/0/projects/{org}/{project}/uptime/{monitor}/{$}", s.getUptimeMonitor)
+	mux.HandleFunc("PUT /api/0/projects/{org}/{project}/uptime/{monitor}/{$}", s.updateUptimeMonitor)
+	mux.HandleFunc("GET /api/0/projects/{org}/{project}/hooks/{$}", s.listServiceHooks)
+	mux.HandleFunc("GET /api/0/projects/{org}/{project}/hooks/{hook}/{$}", s.getServiceHook)
+	mux.HandleFunc("GET /api/0/projects/{org}/{project}/plugins/{plugin}/{$}", s.getPlugin)
+	for _, kind := range []string{client.OrganizationAvatar, client.TeamAvatar, client.ProjectAvatar, client.UserAvatar} {
+		mux.HandleFunc("GET /"+kind+"/{avatar}/{$}", s.getAvatar)
+	}
+	mux.HandleFunc("GET /api/0/projects/{org}/{project}/rules/{$}", s.listIssueAlertRules)
+	mux.HandleFunc("GET /api/0/projects/{org}/{project}/rules/{rule}/{$}", s.getIssueAlertRule)
+	mux.HandleFunc("PUT /api/0/projects/{org}/{project}/rules/{rule}/{$}", s.updateIssueAlertRule)
+
+	s.Server = httptest.NewServer(s.middleware(mux))
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// AddOrganization adds an organization.
+func (s *Server) AddOrganization(slug, name string) client.Organization {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	org := &organization{
+		org: client.Organization{
+			ID:           s.newID(),
+			Slug:         slug,
+			Name:         name,
+			DateCreated:  time.Now().UTC(),
+			Status:       client.OrganizationStatus{ID: "active", Name: "active"},
+			Avatar:       client.Avatar{AvatarType: client.AvatarTypeLetter},
+			OrgRoleList:  slices.Clone(orgRoles),
+			TeamRoleList: slices.Clone(teamRoles),
+		},
+	}
+	s.orgs = append(s.orgs, org)
+
+	return org.org
+}
+
+// AddToken makes the server accept another API token, reaching only the given organizations, by slug. Like a
+// token of a Sentry user, it lists the organizations it reaches and is refused by the others.
+func (s *Server) AddToken(token string, orgs ...string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for _, org := range orgs {
+		s.mustOrg(org)
+	}
+	s.tokens[token] = append(s.tokens[token], orgs...)
+}
+
+// RestrictOrganization makes an organization unreachable with Token, only the tokens added for it reach it.
+func (s *Server) RestrictOrganization(org string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.mustOrg(org).restricted = true
+}
+
+// UploadAvatar stores an image of an organization and returns the avatar to set on the organization, or on one of its
+// teams, projects or members.
+func (s *Server) UploadAvatar(org, contentType string, data []byte) client.Avatar {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.newID()
+	avatarUUID := fmt.Sprintf("%032d", s.nextID)
+	s.avatars[avatarUUID] = &avatar{org: s.mustOrg(org), contentType: contentType, data: slices.Clone(data)}
+
+	return client.Avatar{AvatarType: client.AvatarTypeUpload, AvatarUUID: &avatarUUID}
+}
+
+// UpdateOrganization changes the settings of an organization.
+func (s *Server) UpdateOrganization(org string, update func(*client.Organization)) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	update(&s.mustOrg(org).org)
+}
+
+// SetAuthProvider configures SSO for the organization.
+func (s *Server) SetAuthProvider(org string, authProvider client.AuthProvider) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	authProvider.ID = s.newID()
+	o.authProvider = &authProvider
+	o.org.HasAuthProvider = true
+}
+
+// AddTrustedRelay makes the organization trust a Relay.
+func (s *Server) AddTrustedRelay(org, name, publicKey, description string) client.TrustedRelay {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	relay := client.TrustedRelay{
+		Name:        name,
+		PublicKey:   publicKey,
+		Description: description,
+		Created:     time.Now().UTC(),
+	}
+	o.org.TrustedRelays = append(o.org.TrustedRelays, relay)
+
+	return relay
+}
+
+// TrustedRelays returns the Relays trusted by the organization.
+func (s *Server) TrustedRelays(org string) []client.TrustedRelay {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return slices.Clone(s.mustOrg(org).org.TrustedRelays)
+}
+
+// AddMember adds a member that accepted their invite.
+func (s *Server) AddMember(org, email, role string) client.DetailedMember {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	member := s.newMember(s.mustOrg(org), email, role)
+	member.User = &client.DetailedMemberUser{
+		ID:       s.newID(),
+		Name:     email,
+		Username: email,
+		Email:    email,
+		IsActive: true,
+	}
+	member.InviteStatus = "approved"
+
+	return *member
+}
+
+// AddInvite adds a member that has not accepted their invite yet.
+func (s *Server) AddInvite(org, email, role string) client.DetailedMember {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	member := s.newMember(s.mustOrg(org), email, role)
+	member.Pending = true
+	member.InviteStatus = "approved"
+
+	return *member
+}
+
+// AddTeam adds a team.
+func (s *Server) AddTeam(org, slug, name string) client.Team {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	team := &client.Team{
+		ID:          s.newID(),
+		Slug:        slug,
+		Name:        name,
+		DateCreated: time.Now().UTC(),
+		HasAccess:   true,
+		Avatar:      client.Avatar{AvatarType: client.AvatarTypeLetter},
+	}
+	o.teams = append(o.teams, team)
+
+	return *team
+}
+
+// AddProject adds a project.
+func (s *Server) AddProject(org, slug, name string) client.DetailedProject {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	project := &client.DetailedProject{
+		ID:          s.newID(),
+		Slug:        slug,
+		Name:        name,
+		DateCreated: time.Now().UTC().Format(time.RFC3339),
+		HasAccess:   true,
+		Status:      "active",
+		Teams:       []client.ProjectTeam{},
+	}
+	o.projects = append(o.projects, project)
+
+	return *project
+}
+
+// AddServiceHook adds a service hook to a project.
+func (s *Server) AddServiceHook(org, project, url string, events ...string) client.ServiceHook {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	p := o.project(project)
+	if p == nil {
+		panic(fmt.Sprintf("sentrytest: unknown project %q", project))
+	}
+	hook := &serviceHook{
+		hook: client.ServiceHook{
+			ID:          s.newID(),
+			URL:         url,
+			Status:      "active",
+			Events:      events,
+			DateCreated: time.Now().UTC().Truncate(time.Second),
+		},
+		project: p.ID,
+	}
+	o.serviceHooks = append(o.serviceHooks, hook)
+
+	return hook.hook
+}
+
+// AddPlugin adds a plugin to a project, with its configuration.
+func (s *Server) AddPlugin(org, project string, plugin client.Plugin) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	p := s.mustOrg(org).project(project)
+	if p == nil {
+		panic(fmt.Sprintf("sentrytest: unknown project %q", project))
+	}
+	if plugin.ID == "" {
+		plugin.ID = plugin.Slug
+	}
+	p.Plugins = append(p.Plugins, plugin)
+}
+
+// SetTeamRole changes the role of a member of a team.
+func (s *Server) SetTeamRole(org, team, memberID, role string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	t := o.team(team)
+	m := o.member(memberID)
+	if t == nil || m == nil {
+		panic(fmt.Sprintf("sentrytest: unknown team %q or member %q", team, memberID))
+	}
+	i := slices.IndexFunc(m.TeamRoles, func(teamRole client.MemberTeamRole) bool { return teamRole.TeamSlug == t.Slug })
+	if i < 0 {
+		panic(fmt.Sprintf("sentrytest: member %q is not in team %q", memberID, team))
+	}
+	m.TeamRoles[i].Role = role
+}
+
+// AddTeamMember adds a member to a team.
+func (s *Server) AddTeamMember(org, team, memberID string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	t := o.team(team)
+	m := o.member(memberID)
+	if t == nil || m == nil {
+		panic(fmt.Sprintf("sentrytest: unknown team %q or member %q", team, memberID))
+	}
+	joinTeam(m, t)
+}
+
+// AddProjectTeam gives a team access to a project.
+func (s *Server) AddProjectTeam(org, project, team string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	p := o.project(project)
+	t := o.team(team)
+	if p == nil || t == nil {
+		panic(fmt.Sprintf("sentrytest: unknown project %q or team %q", project, team))
+	}
+	addProjectTeam(p, t)
+}
+
+// AddIssue adds an unresolved issue, assigned to a member unless assigneeID is empty.
+func (s *Server) AddIssue(org, title, assigneeID string) client.Issue {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	issue := &client.Issue{
+		ID:     s.newID(),
+		Title:  title,
+		Status: "unresolved",
+	}
+	issue.ShortID = fmt.Sprintf("%s-%s", strings.ToUpper(o.org.Slug), issue.ID)
+	if assigneeID != "" {
+		issue.AssignedTo = userActor(o.mustUser(assigneeID))
+	}
+	o.issues = append(o.issues, issue)
+
+	return *issue
+}
+
+// AddMetricAlertRule adds a metric alert rule, owned by a member unless ownerID is empty.
+func (s *Server) AddMetricAlertRule(org, name, ownerID string) client.AlertRule {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	return s.addAlertRule(s.mustOrg(org), "", name, ownerID)
+}
+
+// AddIssueAlertRule adds an issue alert rule to a project, owned by a member unless ownerID is empty.
+func (s *Server) AddIssueAlertRule(org, project, name, ownerID string) client.AlertRule {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	p := o.project(project)
+	if p == nil {
+		panic(fmt.Sprintf("sentrytest: unknown project %q", project))
+	}
+
+	return s.addAlertRule(o, p.Slug, name, ownerID)
+}
+
+// AddMonitor adds a cron monitor to a project, owned by a member unless ownerID is empty.
+func (s *Server) AddMonitor(org, project, slug, name, ownerID string) client.Monitor {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	p := o.project(project)
+	if p == nil {
+		panic(fmt.Sprintf("sentrytest: unknown project %q", project))
+	}
+	monitor := &client.Monitor{
+		ID:          s.newID(),
+		Slug:        slug,
+		Name:        name,
+		Status:      "active",
+		DateCreated: time.Now().UTC(),
+		Project:     client.MonitorProject{ID: p.ID, Slug: p.Slug, Name: p.Name},
+	}
+	if ownerID != "" {
+		monitor.Owner = userActor(o.mustUser(ownerID))
+	}
+	o.monitors = append(o.monitors, monitor)
+
+	return *monitor
+}
+
+// AddUptimeMonitor adds an uptime monitor to a project, owned by a member unless ownerID is empty.
+func (s *Server) AddUptimeMonitor(org, project, name, url, ownerID string) client.UptimeMonitor {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	p := o.project(project)
+	if p == nil {
+		panic(fmt.Sprintf("sentrytest: unknown project %q", project))
+	}
+	monitor := &client.UptimeMonitor{
+		ID:          s.newID(),
+		ProjectSlug: p.Slug,
+		Name:        name,
+		Status:      "active",
+		URL:         url,
+	}
+	if ownerID != "" {
+		monitor.Owner = userActor(o.mustUser(ownerID))
+	}
+	o.uptimeMonitors = append(o.uptimeMonitors, monitor)
+
+	return *monitor
+}
+
+// AddExternalUser links a member to their identity in an integration.
+func (s *Server) AddExternalUser(org, memberID, provider, externalName string) client.ExternalUser {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	externalUser := &client.ExternalUser{
+		ID:            s.newID(),
+		Provider:      provider,
+		ExternalName:  externalName,
+		ExternalID:    s.newID(),
+		UserID:        o.mustUser(memberID).ID,
+		IntegrationID: "1",
+	}
+	o.externalUsers = append(o.externalUsers, externalUser)
+
+	return *externalUser
+}
+
+// SetMemberFlags replaces the flags of a member, e.g. to mark them as provisioned by the identity provider.
+func (s *Server) SetMemberFlags(org, memberID string, flags client.DetailedMemberFlags) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	m := s.mustOrg(org).member(memberID)
+	if m == nil {
+		panic(fmt.Sprintf("sentrytest: unknown member %q", memberID))
+	}
+	m.Flags = flags
+}
+
+// RenameTeam changes the slug of a team, memberships follow the team.
+func (s *Server) RenameTeam(org, team, slug string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	t := o.team(team)
+	if t == nil {
+		panic(fmt.Sprintf("sentrytest: unknown team %q", team))
+	}
+	for _, m := range o.members {
+		for i := range m.Teams {
+			if m.Teams[i] == t.Slug {
+				m.Teams[i] = slug
+			}
+		}
+		for i := range m.TeamRoles {
+			if m.TeamRoles[i].TeamSlug == t.Slug {
+				m.TeamRoles[i].TeamSlug = slug
+			}
+		}
+	}
+	t.Slug = slug
+}
+
+// SetTeamIDPProvisioned marks a team as provisioned by the identity provider, its members can't be changed
+// through the API anymore.
+func (s *Server) SetTeamIDPProvisioned(org, team string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	t := s.mustOrg(org).team(team)
+	if t == nil {
+		panic(fmt.Sprintf("sentrytest: unknown team %q", team))
+	}
+	t.Flags.IDPProvisioned = true
+}
+
+// AddAccessRequest adds a request for a member to join a team, made by another member unless requesterID is
+// empty.
+func (s *Server) AddAccessRequest(org, memberID, team, requesterID string) client.AccessRequest {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	m := o.member(memberID)
+	if m == nil {
+		panic(fmt.Sprintf("sentrytest: unknown member %q", memberID))
+	}
+	t := o.team(team)
+	if t == nil {
+		panic(fmt.Sprintf("sentrytest: unknown team %q", team))
+	}
+	request := &client.AccessRequest{
+		ID:     s.newID(),
+		Member: m.OrganizationMember(),
+		Team:   *t,
+	}
+	if requesterID != "" {
+		u := o.mustUser(requesterID)
+		request.Requester = &client.User{ID: u.ID, Name: u.Name, Username: u.Username, Email: u.Email}
+	}
+	o.accessRequests = append(o.accessRequests, request)
+
+	return *request
+}
+
+// AddInviteRequest adds a request to invite email, made by a member unless inviterID is empty, in which case
+// the invitee asked to join.
+func (s *Server) AddInviteRequest(org, email, role, inviterID string, teams ...string) client.InviteRequest {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	request := &client.DetailedMember{
+		ID:           s.newID(),
+		Email:        email,
+		Name:         email,
+		Role:         role,
+		OrgRole:      role,
+		Pending:      true,
+		DateCreated:  time.Now().UTC(),
+		InviteStatus: "requested_to_join",
+		Teams:        []string{},
+		TeamRoles:    []client.MemberTeamRole{},
+	}
+	if inviterID != "" {
+		request.InviteStatus = "requested_to_be_invited"
+		request.InviterName = o.mustUser(inviterID).Name
+	}
+	for _, team := range teams {
+		t := o.team(team)
+		if t == nil {
+			panic(fmt.Sprintf("sentrytest: unknown team %q", team))
+		}
+		request.Teams = append(request.Teams, t.Slug)
+	}
+	o.inviteRequests = append(o.inviteRequests, request)
+
+	return inviteRequest(request)
+}
+
+// AccessRequests returns the pending requests to join a team.
+func (s *Server) AccessRequests(org string) []client.AccessRequest {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	requests := []client.AccessRequest{}
+	for _, r := range s.mustOrg(org).accessRequests {
+		requests = append(requests, *r)
+	}
+	return requests
+}
+
+// InviteRequests returns the pending invite requests.
+func (s *Server) InviteRequests(org string) []client.InviteRequest {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	requests := []client.InviteRequest{}
+	for _, r := range s.mustOrg(org).inviteRequests {
+		requests = append(requests, inviteRequest(r))
+	}
+	return requests
+}
+
+// SetOwnershipRules replaces the ownership rules of a project.
+func (s *Server) SetOwnershipRules(org, project, raw string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	p := o.project(project)
+	if p == nil {
+		panic(fmt.Sprintf("sentrytest: unknown project %q", project))
+	}
+	now := time.Now().UTC()
+	if o.ownership == nil {
+		o.ownership = map[string]*client.ProjectOwnership{}
+	}
+	o.ownership[p.ID] = &client.ProjectOwnership{
+		Raw:            raw,
+		FallThrough:    true,
+		AutoAssignment: "Auto Assign to Issue Owner",
+		IsActive:       true,
+		DateCreated:    &now,
+		LastUpdated:    &now,
+	}
+}
+
+// AddCodeOwners imports a CODEOWNERS file into a project, ownershipSyntax is the file translated to Sentry's
+// ownership syntax.
+func (s *Server) AddCodeOwners(org, project, raw, ownershipSyntax string) client.CodeOwners {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	p := o.project(project)
+	if p == nil {
+		panic(fmt.Sprintf("sentrytest: unknown project %q", project))
+	}
+	codeowners := client.CodeOwners{
+		ID:              s.newID(),
+		Raw:             raw,
+		Provider:        "github",
+		CodeMappingID:   s.newID(),
+		OwnershipSyntax: ownershipSyntax,
+		DateCreated:     time.Now().UTC(),
+		DateUpdated:     time.Now().UTC(),
+	}
+	o.codeowners = append(o.codeowners, &codeOwners{codeowners: codeowners, project: p.ID})
+
+	return codeowners
+}
+
+// DisableCodeOwners makes the organization's plan lack CODEOWNERS support, listing them is forbidden.
+func (s *Server) DisableCodeOwners(org string) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	s.mustOrg(org).codeownersDisabled = true
+}
+
+// AddDashboard adds a custom dashboard, permissions may be nil like for dashboards whose edit access was never
+// restricted.
+func (s *Server) AddDashboard(org, title string, permissions *client.DashboardPermissions) client.Dashboard {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	dashboard := &client.Dashboard{
+		ID:          s.newID(),
+		Title:       title,
+		DateCreated: time.Now().UTC(),
+		Permissions: permissions,
+	}
+	o.dashboards = append(o.dashboards, dashboard)
+
+	return *dashboard
+}
+
+// Member returns the current state of a member.
+func (s *Server) Member(org, memberID string) (client.DetailedMember, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	m := s.mustOrg(org).member(memberID)
+	if m == nil {
+		return client.DetailedMember{}, false
+	}
+
+	return *m, true
+}
+
+// MemberByEmail returns the current state of the member with the given email.
+func (s *Server) MemberByEmail(org, email string) (client.DetailedMember, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	m := s.mustOrg(org).memberByEmail(email)
+	if m == nil {
+		return client.DetailedMember{}, false
+	}
+
+	return *m, true
+}
+
+// Project returns the current state of a project.
+func (s *Server) Project(org, project string) (client.DetailedProject, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	p := s.mustOrg(org).project(project)
+	if p == nil {
+		return client.DetailedProject{}, false
+	}
+
+	return *p, true
+}
+
+// Issue returns the current state of an issue.
+func (s *Server) Issue(org, id string) (client.Issue, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	i := s.mustOrg(org).issue(id)
+	if i == nil {
+		return client.Issue{}, false
+	}
+
+	return *i, true
+}
+
+// AlertRule returns the current state of a metric or issue alert rule.
+func (s *Server) AlertRule(org, id string) (client.AlertRule, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	r := s.mustOrg(org).alertRule("", id)
+	if r == nil {
+		return client.AlertRule{}, false
+	}
+
+	return r.rule, true
+}
+
+// Monitor returns the current state of a cron monitor.
+func (s *Server) Monitor(org, slug string) (client.Monitor, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	m := s.mustOrg(org).monitor(slug)
+	if m == nil {
+		return client.Monitor{}, false
+	}
+
+	return *m, true
+}
+
+// UptimeMonitor returns the current state of an uptime monitor.
+func (s *Server) UptimeMonitor(org, id string) (client.UptimeMonitor, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	m := s.mustOrg(org).uptimeMonitor("", id)
+	if m == nil {
+		return client.UptimeMonitor{}, false
+	}
+
+	return *m, true
+}
+
+// Dashboard returns the current state of a dashboard.
+func (s *Server) Dashboard(org, id string) (client.Dashboard, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	d := s.mustOrg(org).dashboard(id)
+	if d == nil {
+		return client.Dashboard{}, false
+	}
+
+	return *d, true
+}
+
+// ExternalUsers returns the external users linked to a member.
+func (s *Server) ExternalUsers(org, memberID string) []client.ExternalUser {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	o := s.mustOrg(org)
+	return o.memberExternalUsers(o.member(memberID))
+}
+
+// RateLimitNext rejects the next n requests with 429 Too Many Requests.
+func (s *Server) RateLimitNext(n int) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.throttled = n
+}
+
+// Requests returns every request served so far as "<method> <path>", rate limited ones included.
+func (s *Server) Requests() []string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return slices.Clone(s.requests)
+}
+
+// Mutations returns the number of requests served so far that could change data.
+func (s *Server) Mutations() int {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	n := 0
+	for _, req := range s.requests {
+		method, _, _ := strings.Cut(req, " ")
+		if method != http.MethodGet {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (s *Server) middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mtx.Lock()
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		throttled := s.throttled > 0
+		if throttled {
+			s.throttled--
+		}
+		limit := s.RateLimit
+		s.mtx.Unlock()
+
+		reset := strconv.FormatInt(time.Now().Add(time.Second).Unix(), 10)
+		w.Header().Set("X-Sentry-Rate-Limit-Limit", strconv.Itoa(limit))
+		w.Header().Set("X-Sentry-Rate-Limit-Reset", reset)
+		w.Header().Set("X-Sentry-Rate-Limit-ConcurrentLimit", "25")
+		w.Header().Set("X-Sentry-Rate-Limit-ConcurrentRemaining", "24")
+
+		if throttled {
+			w.Header().Set("X-Sentry-Rate-Limit-Remaining", "0")
+			w.Header().Set("Retry-After", "0")
+			writeError(w, http.StatusTooManyRequests, "You are attempting to use this endpoint too frequently. Limit is 40 requests in 1 seconds")
+			return
+		}
+		w.Header().Set("X-Sentry-Rate-Limit-Remaining", strconv.Itoa(limit-1))
+
+		if !s.validToken(r) {
+			writeError(w, http.StatusUnauthorized, "Invalid token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// validToken checks the token of a request. SCIM endpoints only accept SCIMToken, the others Token and the tokens
+// added with AddToken.
+func (s *Server) validToken(r *http.Request) bool {
+	token := requestToken(r)
+	if strings.Contains(r.URL.Path, "/scim/v2/") {
+		return token == SCIMToken
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	_, ok := s.tokens[token]
+	return token == Token || ok
+}
+
+// reaches tells whether the token of a request reaches an organization.
+func (s *Server) reaches(r *http.Request, o *organization) bool {
+	switch token := requestToken(r); token {
+	case SCIMToken:
+		return true
+	case Token:
+		return !o.restricted
+	default:
+		return slices.Contains(s.tokens[token], o.org.Slug)
+	}
+}
+
+func requestToken(r *http.Request) string {
+	return strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+}
+
+func (s *Server) newID() string {
+	s.nextID++
+	return strconv.Itoa(s.nextID)
+}
+
+func (s *Server) newMember(o *organization, email, role string) *client.DetailedMember {
+	if role == "" {
+		role = "member"
+	}
+
+	member := &client.DetailedMember{
+		ID:          s.newID(),
+		Email:       email,
+		Name:        email,
+		Role:        role,
+		OrgRole:     role,
+		DateCreated: time.Now().UTC(),
+		Teams:       []string{},
+		TeamRoles:   []client.MemberTeamRole{},
+	}
+	o.members = append(o.members, member)
+
+	return member
+}
+
+func (s *Server) addAlertRule(o *organization, project, name, ownerID string) client.AlertRule {
+	rule := &alertRule{
+		rule: client.AlertRule{
+			ID:          s.newID(),
+			Name:        name,
+			DateCreated: time.Now().UTC(),
+			Projects:    []string{},
+		},
+		project: project,
+	}
+	if project != "" {
+		rule.rule.Projects = []string{project}
+	}
+	if ownerID != "" {
+		owner := client.UserActor(o.mustUser(ownerID).ID)
+		rule.rule.Owner = &owner
+	}
+	o.alertRules = append(o.alertRules, rule)
+
+	return rule.rule
+}
+
+func (s *Server) mustOrg(idOrSlug string) *organization {
+	o := s.org(idOrSlug)
+	if o == nil {
+		panic(fmt.Sprintf("sentrytest: unknown organization %q", idOrSlug))
+	}
+	return o
+}
+
+func (s *Server) org(idOrSlug string) *organization {
+	for _, o := range s.orgs {
+		if o.org.ID == idOrSlug || o.org.Slug == idOrSlug {
+			return o
+		}
+	}
+	return nil
+}
+
+func (o *organization) member(id string) *client.DetailedMember {
+	for _, m := range o.members {
+		if m.ID == id {
+			return m
+		}
+	}
+	return nil
+}
+
+// mustUser returns the user of a member that accepted their invite.
+func (o *organization) mustUser(memberID string) *client.DetailedMemberUser {
+	m := o.member(memberID)
+	if m == nil || m.User == nil {
+		panic(fmt.Sprintf("sentrytest: unknown member %q or member without a user", memberID))
+	}
+	return m.User
+}
+
+func (o *organization) memberByUserID(userID string) *client.DetailedMember {
+	for _, m := range o.members {
+		if m.User != nil && m.User.ID == userID {
+			return m
+		}
+	}
+	return nil
+}
+
+// isOnlyOwner reports whether the member is the only owner that accepted their invite.
+func (o *organization) isOnlyOwner(m *client.DetailedMember) bool {
+	if m.OrgRole != client.OrgRoleOwner || m.Pending {
+		return false
+	}
+	for _, other := range o.members {
+		if other != m && other.OrgRole == client.OrgRoleOwner && !other.Pending {
+			return false
+		}
+	}
+	return true
+}
+
+func (o *organization) memberByEmail(email string) *client.DetailedMember {
+	for _, m := range o.members {
+		if m.Email == email {
+			return m
+		}
+	}
+	return nil
+}
+
+func (o *organization) team(idOrSlug string) *client.Team {
+	for _, t := range o.teams {
+		if t.ID == idOrSlug || t.Slug == idOrSlug {
+			return t
+		}
+	}
+	return nil
+}
+
+func (o *organization) project(idOrSlug string) *client.DetailedProject {
+	for _, p := range o.projects {
+		if p.ID == idOrSlug || p.Slug == idOrSlug {
+			return p
+		}
+	}
+	return nil
+}
+
+func (o *organization) issue(id string) *client.Issue {
+	for _, i := range o.issues {
+		if i.ID == id {
+			return i
+		}
+	}
+	return nil
+}
+
+// alertRule finds a metric alert rule when project is empty and an issue alert rule of the project otherwise.
+// Any rule matches when project is "".
+func (o *organization) alertRule(project, id string) *alertRule {
+	for _, r := range o.alertRules {
+		if r.rule.ID == id && (project == "" || r.project == project) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (o *organization) monitor(idOrSlug string) *client.Monitor {
+	for _, m := range o.monitors {
+		if m.ID == idOrSlug || m.Slug == idOrSlug {
+			return m
+		}
+	}
+	return nil
+}
+
+// uptimeMonitor finds an uptime monitor of a project, of any project when projectSlug is "".
+func (o *organization) uptimeMonitor(projectSlug, id string) *client.UptimeMonitor {
+	for _, m := range o.uptimeMonitors {
+		if m.ID == id && (projectSlug == "" || m.ProjectSlug == projectSlug) {
+			return m
+		}
+	}
+	return nil
+}
+
+func inviteRequest(m *client.DetailedMember) client.InviteRequest {
+	return client.InviteRequest{
+		ID:           m.ID,
+		Email:        m.Email,
+		OrgRole:      m.OrgRole,
+		InviteStatus: m.InviteStatus,
+		InviterName:  m.InviterName,
+		Teams:        m.Teams,
+		DateCreated:  m.DateCreated,
+	}
+}
+
+func (o *organization) dashboard(id string) *client.Dashboard {
+	for _, d := range o.dashboards {
+		if d.ID == id {
+			return d
+		}
+	}
+	return nil
+}
+
+func (o *organization) memberExternalUsers(m *client.DetailedMember) []client.ExternalUser {
+	externalUsers := []client.ExternalUser{}
+	if m == nil || m.User == nil {
+		return externalUsers
+	}
+	for _, e := range o.externalUsers {
+		if e.UserID == m.User.ID {
+			externalUsers = append(externalUsers, *e)
+		}
+	}
+	return externalUsers
+}
+
+// actor resolves a "user:<user id>" or "team:<team id>" actor to an issue assignee.
+func (o *organization) actor(actor string) (*client.Actor, bool) {
+	kind, id, _ := strings.Cut(actor, ":")
+	switch kind {
+	case "user":
+		if m := o.memberByUserID(id); m != nil {
+			return userActor(m.User), true
+		}
+	case "team":
+		if t := o.team(id); t != nil {
+			return &client.Actor{Type: "team", ID: t.ID, Name: t.Name}, true
+		}
+	}
+	return nil, false
+}
+
+func userActor(u *client.DetailedMemberUser) *client.Actor {
+	return &client.Actor{Type: "user", ID: u.ID, Name: u.Name, Email: u.Email}
+}
+
+func isTeamMember(m *client.DetailedMember, t *client.Team) bool {
+	return slices.Contains(m.Teams, t.Slug)
+}
+
+func joinTeam(m *client.DetailedMember, t *client.Team) bool {
+	if isTeamMember(m, t) {
+		return false
+	}
+
+	m.Teams = append(m.Teams, t.Slug)
+	m.TeamRoles = append(m.TeamRoles, client.MemberTeamRole{TeamSlug: t.Slug, Role: "contributor"})
+	t.MemberCount++
+
+	return true
+}
+
+func leaveTeam(m *client.DetailedMember, t *client.Team) bool {
+	if !isTeamMember(m, t) {
+		return false
+	}
+
+	m.Teams = slices.DeleteFunc(m.Teams, func(slug string) bool { return slug == t.Slug })
+	m.TeamRoles = slices.DeleteFunc(m.TeamRoles, func(role client.MemberTeamRole) bool { return role.TeamSlug == t.Slug })
+	t.MemberCount--
+
+	return true
+}
+
+func hasProjectTeam(p *client.DetailedProject, t *client.Team) bool {
+	return slices.ContainsFunc(p.Teams, func(team client.ProjectTeam) bool { return team.ID == t.ID })
+}
+
+func addProjectTeam(p *client.DetailedProject, t *client.Team) {
+	if hasProjectTeam(p, t) {
+		return
+	}
+	p.Teams = append(p.Teams, client.ProjectTeam{ID: t.ID, Name: t.Name, Slug: t.Slug})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, detail string) {
+	writeJSON(w, status, map[string]string{"detail": detail})
+}